@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const diPath = "github.com/gocrud/csgo/di"
+
+// reservedAccessors 与 *di.StaticProvider 方法或字段同名，不生成类型化访问方法
+var reservedAccessors = map[string]bool{
+	"Get": true, "GetNamed": true, "CreateScope": true, "Dispose": true,
+	"DisposeAsync": true, "Scope": true, "StaticProvider": true,
+}
+
+// simpleType 匹配可以生成访问方法名的类型：T、*T、pkg.T、*pkg.T
+var simpleType = regexp.MustCompile(`^\*?(?:\w+\.)?(\w+)$`)
+
+// generator 校验依赖并生成容器代码
+type generator struct {
+	pkg      *pkgInfo
+	typeName string
+	bindings map[string]*provider // 类型标识 -> 最后注册的 provider（与 di 一致：后注册者优先）
+	funcs    map[*provider]string // provider -> 生成的解析函数名
+}
+
+func newGenerator(pkg *pkgInfo, typeName string) *generator {
+	g := &generator{
+		pkg:      pkg,
+		typeName: typeName,
+		bindings: make(map[string]*provider),
+		funcs:    make(map[*provider]string),
+	}
+
+	used := make(map[string]bool)
+	for _, p := range pkg.providers {
+		g.bindings[p.service.key] = p
+		for _, alias := range p.aliases {
+			g.bindings[alias.key] = p
+		}
+
+		name := "resolve" + strings.ToUpper(p.fn[:1]) + p.fn[1:]
+		if used[name] {
+			name = fmt.Sprintf("%s%d", name, len(used))
+		}
+		used[name] = true
+		g.funcs[p] = name
+	}
+	return g
+}
+
+// validate 检查缺失依赖、生命周期违规和循环依赖，一次性返回所有问题
+func (g *generator) validate() error {
+	var errs []string
+	for _, p := range g.pkg.providers {
+		for i, param := range p.params {
+			target, ok := g.bindings[param.key]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: %s: parameter %d (%s) has no %s constructor",
+					shortPos(p.pos), p.fn, i+1, param.expr, directive))
+				continue
+			}
+			if p.lifetime == "Singleton" && target.lifetime != "Singleton" {
+				errs = append(errs, fmt.Sprintf("%s: %s: singleton service cannot depend on %s service %s",
+					shortPos(p.pos), p.fn, strings.ToLower(target.lifetime), param.expr))
+			}
+		}
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		errs = append(errs, "circular dependency detected: "+strings.Join(cycle, " -> "))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// findCycle 返回第一个依赖环（构造函数名称），没有环时返回 nil
+func (g *generator) findCycle() []string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[*provider]int)
+	var stack []string
+
+	var visit func(p *provider) []string
+	visit = func(p *provider) []string {
+		switch state[p] {
+		case done:
+			return nil
+		case visiting:
+			for i, fn := range stack {
+				if fn == p.fn {
+					return append(append([]string{}, stack[i:]...), p.fn)
+				}
+			}
+		}
+
+		state[p] = visiting
+		stack = append(stack, p.fn)
+		for _, param := range p.params {
+			if target, ok := g.bindings[param.key]; ok {
+				if cycle := visit(target); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[p] = done
+		return nil
+	}
+
+	for _, p := range g.pkg.providers {
+		if cycle := visit(p); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// generate 生成格式化后的容器代码
+func (g *generator) generate() ([]byte, error) {
+	var b bytes.Buffer
+	w := func(format string, args ...any) { fmt.Fprintf(&b, format, args...) }
+
+	w("%s\n\npackage %s\n\nimport (\n", generatedHeader, g.pkg.name)
+	// 标准库在前，第三方包在后
+	for _, std := range []bool{true, false} {
+		for _, p := range g.pkg.imports.paths() {
+			if isStdlib(p) != std {
+				continue
+			}
+			if name := g.pkg.imports.byPath[p]; name != path.Base(p) {
+				w("\t%s %q\n", name, p)
+			} else {
+				w("\t%q\n", p)
+			}
+		}
+		if std {
+			w("\n")
+		}
+	}
+	w(")\n\n")
+
+	// 容器类型与构造函数
+	w("// %s 是由 csgo-di 生成的服务容器，实现 di.IServiceProvider。\n", g.typeName)
+	w("// 服务通过直接调用构造函数创建，di.Get[T] 等 API 可以照常使用。\n")
+	w("type %s struct {\n\t*di.StaticProvider\n}\n\n", g.typeName)
+
+	w("// New%s 创建容器，并按注册顺序提前创建所有单例（失败时 panic）。\n", g.typeName)
+	w("func New%s() *%s {\n", g.typeName, g.typeName)
+	w("\treturn &%s{StaticProvider: di.NewStaticProvider(%d, []di.StaticService{\n", g.typeName, len(g.pkg.providers))
+	for _, p := range g.pkg.providers {
+		w("\t\t{Type: reflect.TypeFor[%s](), Lifetime: di.%s, Resolve: func(s *di.StaticScope) (any, error) { return %s(s) }},\n",
+			p.service.expr, p.lifetime, g.funcs[p])
+		for _, alias := range p.aliases {
+			w("\t\t{Type: reflect.TypeFor[%s](), Lifetime: di.%s, Resolve: func(s *di.StaticScope) (any, error) {\n", alias.expr, p.lifetime)
+			w("\t\t\tv, err := %s(s)\n\t\t\tvar service %s = v\n\t\t\treturn service, err\n\t\t}},\n", g.funcs[p], alias.expr)
+		}
+	}
+	w("\t})}\n}\n")
+
+	// 类型化访问方法（不经过反射）
+	for _, a := range g.accessors() {
+		w("\n// %s 解析 %s。\n", a.name, a.provider.service.expr)
+		w("func (c *%s) %s() %s {\n", g.typeName, a.name, a.provider.service.expr)
+		w("\treturn di.StaticMust(%s(c.Scope()))\n}\n", g.funcs[a.provider])
+	}
+
+	// 每个构造函数一个解析函数
+	for slot, p := range g.pkg.providers {
+		w("\nfunc %s(s *di.StaticScope) (%s, error) {\n", g.funcs[p], p.service.expr)
+		if p.lifetime == "Transient" {
+			w("\treturn di.StaticTransient(s, func() (v %s, err error) {\n", p.service.expr)
+		} else {
+			w("\treturn di.Static%s(s, %d, func() (v %s, err error) {\n", p.lifetime, slot, p.service.expr)
+		}
+
+		args := make([]string, len(p.params))
+		for i, param := range p.params {
+			args[i] = fmt.Sprintf("p%d", i)
+			w("\t\tp%d, err := %s(s)\n\t\tif err != nil {\n\t\t\treturn v, err\n\t\t}\n", i, g.funcs[g.bindings[param.key]])
+		}
+
+		call := fmt.Sprintf("%s(%s)", p.fn, strings.Join(args, ", "))
+		if p.returnsErr {
+			w("\t\treturn %s\n", call)
+		} else {
+			w("\t\treturn %s, nil\n", call)
+		}
+		w("\t})\n}\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return src, nil
+}
+
+// accessor 类型化访问方法
+type accessor struct {
+	name     string
+	provider *provider
+}
+
+// accessors 返回 Singleton 和 Transient 服务的访问方法，方法名取自类型名
+// 名称冲突或与 StaticProvider 方法同名时不生成；Scoped 服务需要通过作用域解析，不生成
+func (g *generator) accessors() []accessor {
+	var result []accessor
+	count := make(map[string]int)
+	for _, p := range g.pkg.providers {
+		if g.bindings[p.service.key] != p || p.lifetime == "Scoped" {
+			continue
+		}
+		m := simpleType.FindStringSubmatch(p.service.expr)
+		if m == nil {
+			continue
+		}
+		name := strings.ToUpper(m[1][:1]) + m[1][1:]
+		if reservedAccessors[name] {
+			continue
+		}
+		count[name]++
+		result = append(result, accessor{name: name, provider: p})
+	}
+
+	unique := result[:0]
+	for _, a := range result {
+		if count[a.name] == 1 {
+			unique = append(unique, a)
+		}
+	}
+	return unique
+}
+
+// isStdlib 判断包路径是否属于标准库（第一段不含 "."）
+func isStdlib(pkgPath string) bool {
+	first, _, _ := strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".")
+}
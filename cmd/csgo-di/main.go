@@ -0,0 +1,81 @@
+// Command csgo-di 为标记了 //csgo:inject 的构造函数生成静态服务容器。
+//
+// 生成的容器直接调用构造函数，不使用 reflect.Value.Call；缺失的依赖、
+// Singleton 依赖 Scoped/Transient 服务以及循环依赖在生成时报告，类型不匹配则由编译器报告。
+// 容器实现 di.IServiceProvider，现有的 di.Get[T] 调用无需修改。
+//
+// 用法：
+//
+//	//go:generate go run github.com/gocrud/csgo/cmd/csgo-di -type Container
+//
+//	//csgo:inject
+//	func NewUserRepository(db *sql.DB) *UserRepository { ... }
+//
+//	//csgo:inject scoped as=IUserService
+//	func NewUserService(repo *UserRepository) *UserService { ... }
+//
+// 指令选项：
+//
+//	singleton | scoped | transient   生命周期，默认 singleton
+//	as=IFoo,IBar                     同时以接口暴露（共享同一实例）
+//
+// 构造函数必须是顶层函数，返回 T 或 (T, error)，每个参数都是一个依赖。
+// 不支持命名服务、参数对象、[]T、Lazy[T] 和 func() T 依赖。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "Container", "name of the generated container type")
+	output := flag.String("output", "", "output file name (default: <type>_gen.go)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: csgo-di [-type Container] [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_gen.go"
+	}
+
+	if err := run(dir, *typeName, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "csgo-di: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run 解析 dir 中的包并将容器写入 dir/output
+func run(dir, typeName, output string) error {
+	src, err := generateContainer(dir, typeName, output)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, output), src, 0o644)
+}
+
+// generateContainer 解析 dir 中的包并返回生成的容器代码
+func generateContainer(dir, typeName, output string) ([]byte, error) {
+	pkg, err := loadPackage(dir, output)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.providers) == 0 {
+		return nil, fmt.Errorf("no %s constructors found in %s", directive, dir)
+	}
+
+	g := newGenerator(pkg, typeName)
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	return g.generate()
+}
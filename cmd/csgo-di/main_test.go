@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateGolden tests that the generated container for testdata/app matches the checked-in file
+func TestGenerateGolden(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+	got, err := generateContainer(dir, "Container", "container_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filepath.Join(dir, "container_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generated code differs from testdata/app/container_gen.go; regenerate with:\n"+
+			"  go run ./cmd/csgo-di ./cmd/csgo-di/testdata/app\ngot:\n%s", got)
+	}
+}
+
+// TestGenerateErrors tests that dependency problems are reported at generate time
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "missing dependency",
+			src: `
+type DB struct{}
+type Repo struct{}
+
+//csgo:inject
+func NewRepo(db *DB) *Repo { return &Repo{} }
+`,
+			want: []string{"NewRepo: parameter 1 (*DB) has no //csgo:inject constructor"},
+		},
+		{
+			name: "captive dependency",
+			src: `
+type Session struct{}
+type Cache struct{}
+
+//csgo:inject scoped
+func NewSession() *Session { return &Session{} }
+
+//csgo:inject
+func NewCache(s *Session) *Cache { return &Cache{} }
+`,
+			want: []string{"NewCache: singleton service cannot depend on scoped service *Session"},
+		},
+		{
+			name: "cycle",
+			src: `
+type A struct{}
+type B struct{}
+
+//csgo:inject
+func NewA(b *B) *A { return &A{} }
+
+//csgo:inject
+func NewB(a *A) *B { return &B{} }
+`,
+			want: []string{"circular dependency detected: NewA -> NewB -> NewA"},
+		},
+		{
+			name: "invalid constructor",
+			src: `
+type A struct{}
+
+//csgo:inject lazy
+func NewA() *A { return &A{} }
+
+//csgo:inject
+func NewPair() (*A, *A) { return nil, nil }
+`,
+			want: []string{`unknown //csgo:inject option "lazy"`, "NewPair: constructor must return T or (T, error)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"+tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := generateContainer(dir, "Container", "container_gen.go")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateAccessors tests that accessors are skipped for scoped, ambiguous and reserved names
+func TestGenerateAccessors(t *testing.T) {
+	dir := t.TempDir()
+	src := `package app
+
+import (
+	"github.com/gocrud/csgo/config"
+	other "example.com/other/config"
+)
+
+type Session struct{}
+type Dispose struct{}
+
+//csgo:inject scoped
+func NewSession() *Session { return &Session{} }
+
+//csgo:inject
+func NewDispose() *Dispose { return &Dispose{} }
+
+//csgo:inject
+func NewConfig() config.IConfiguration { return nil }
+
+//csgo:inject
+func NewOtherConfig() other.IConfiguration { return nil }
+`
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := generateContainer(dir, "Services", "services_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(out)
+
+	for _, unwanted := range []string{") Session()", ") Dispose()", ") IConfiguration()"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("expected no accessor %q, got:\n%s", unwanted, code)
+		}
+	}
+	for _, want := range []string{"type Services struct", "func NewServices() *Services", `other "example.com/other/config"`} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, code)
+		}
+	}
+}
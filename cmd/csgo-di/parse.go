@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// directive 标记需要生成的构造函数
+//
+//	//csgo:inject [singleton|scoped|transient] [as=IFoo,IBar]
+const directive = "//csgo:inject"
+
+// generatedHeader 生成文件的首行，解析时跳过这类文件
+const generatedHeader = "// Code generated by csgo-di. DO NOT EDIT."
+
+// typeRef 类型引用
+type typeRef struct {
+	key  string // 以包路径限定的类型标识，用于匹配依赖
+	expr string // 生成代码中使用的类型表达式
+}
+
+// provider 一个标记了 csgo:inject 的构造函数
+type provider struct {
+	fn         string
+	pos        token.Position
+	lifetime   string // Singleton、Scoped 或 Transient（与 di 中的常量同名）
+	service    typeRef
+	aliases    []typeRef // 通过 as= 额外暴露的接口
+	params     []typeRef
+	returnsErr bool
+}
+
+// pkgInfo 解析后的包
+type pkgInfo struct {
+	name      string
+	providers []*provider
+	imports   *importSet
+}
+
+// importSet 生成文件需要导入的包
+type importSet struct {
+	byPath  map[string]string // 包路径 -> 生成代码中使用的名称
+	byAlias map[string]string // 名称 -> 包路径
+}
+
+func newImportSet() *importSet {
+	s := &importSet{byPath: map[string]string{}, byAlias: map[string]string{}}
+	// 生成代码总是需要 reflect 和 di
+	s.byPath["reflect"], s.byAlias["reflect"] = "reflect", "reflect"
+	s.byPath[diPath], s.byAlias["di"] = "di", diPath
+	return s
+}
+
+// use 记录源文件中以 alias 引用的包，返回生成代码中使用的名称
+func (s *importSet) use(alias, pkgPath string) (string, error) {
+	if name, ok := s.byPath[pkgPath]; ok {
+		return name, nil
+	}
+	if other, ok := s.byAlias[alias]; ok {
+		return "", fmt.Errorf("package name %s refers to both %q and %q; use distinct import aliases", alias, other, pkgPath)
+	}
+	s.byPath[pkgPath], s.byAlias[alias] = alias, pkgPath
+	return alias, nil
+}
+
+// paths 按包路径排序返回所有导入
+func (s *importSet) paths() []string {
+	paths := make([]string, 0, len(s.byPath))
+	for p := range s.byPath {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// loadPackage 解析目录中的 Go 文件（跳过测试文件、生成文件和不满足构建约束的文件），收集所有标记的构造函数
+func loadPackage(dir, output string) (*pkgInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkg := &pkgInfo{imports: newImportSet()}
+	var errs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(src, []byte(generatedHeader)) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		} else if pkg.name != file.Name.Name {
+			return nil, fmt.Errorf("found packages %s and %s in %s", pkg.name, file.Name.Name, dir)
+		}
+
+		for _, e := range pkg.collect(fset, file) {
+			errs = append(errs, e.Error())
+		}
+	}
+
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return pkg, nil
+}
+
+// collect 收集文件中标记的构造函数
+func (pkg *pkgInfo) collect(fset *token.FileSet, file *ast.File) []error {
+	imports := fileImports(file)
+
+	var errs []error
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil {
+			continue
+		}
+		options, ok := findDirective(fn.Doc)
+		if !ok {
+			continue
+		}
+
+		pos := fset.Position(fn.Pos())
+		p, err := pkg.newProvider(fn, options, imports)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %v", shortPos(pos), fn.Name.Name, err))
+			continue
+		}
+		p.pos = pos
+		pkg.providers = append(pkg.providers, p)
+	}
+	return errs
+}
+
+// newProvider 从函数声明和指令选项创建 provider
+func (pkg *pkgInfo) newProvider(fn *ast.FuncDecl, options []string, imports map[string]string) (*provider, error) {
+	if fn.Recv != nil {
+		return nil, fmt.Errorf("%s must annotate a top-level function", directive)
+	}
+	if fn.Type.TypeParams != nil {
+		return nil, fmt.Errorf("generic constructors are not supported")
+	}
+
+	p := &provider{fn: fn.Name.Name, lifetime: "Singleton"}
+
+	// 返回值：T 或 (T, error)
+	results := fieldTypes(fn.Type.Results)
+	switch {
+	case len(results) == 1:
+	case len(results) == 2 && isErrorType(results[1]):
+		p.returnsErr = true
+	default:
+		return nil, fmt.Errorf("constructor must return T or (T, error)")
+	}
+	service, err := renderType(results[0], imports, pkg.imports)
+	if err != nil {
+		return nil, fmt.Errorf("result: %v", err)
+	}
+	p.service = service
+
+	// 参数：每个参数都是一个依赖
+	for i, expr := range fieldTypes(fn.Type.Params) {
+		if _, ok := expr.(*ast.Ellipsis); ok {
+			return nil, fmt.Errorf("variadic parameters are not supported")
+		}
+		param, err := renderType(expr, imports, pkg.imports)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d: %v", i+1, err)
+		}
+		p.params = append(p.params, param)
+	}
+
+	// 指令选项
+	for _, opt := range options {
+		switch {
+		case opt == "singleton" || opt == "scoped" || opt == "transient":
+			p.lifetime = strings.ToUpper(opt[:1]) + opt[1:]
+		case strings.HasPrefix(opt, "as="):
+			for _, name := range strings.Split(strings.TrimPrefix(opt, "as="), ",") {
+				expr, err := parser.ParseExpr(name)
+				if err != nil {
+					return nil, fmt.Errorf("invalid type %q in as=", name)
+				}
+				alias, err := renderType(expr, imports, pkg.imports)
+				if err != nil {
+					return nil, fmt.Errorf("as=%s: %v", name, err)
+				}
+				p.aliases = append(p.aliases, alias)
+			}
+		default:
+			return nil, fmt.Errorf("unknown %s option %q", directive, opt)
+		}
+	}
+	return p, nil
+}
+
+// findDirective 查找 csgo:inject 指令并返回其选项
+func findDirective(doc *ast.CommentGroup) ([]string, bool) {
+	for _, c := range doc.List {
+		if c.Text == directive || strings.HasPrefix(c.Text, directive+" ") {
+			return strings.Fields(strings.TrimPrefix(c.Text, directive)), true
+		}
+	}
+	return nil, false
+}
+
+// fileImports 返回文件中包名到包路径的映射（未指定别名时使用路径的最后一段）
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		pkgPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(pkgPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		imports[name] = pkgPath
+	}
+	return imports
+}
+
+// fieldTypes 展开字段列表，每个名称对应一个类型
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+	var types []ast.Expr
+	for _, f := range fields.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, f.Type)
+		}
+	}
+	return types
+}
+
+func isErrorType(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// renderType 将类型表达式转换为 typeRef，并记录引用的包
+func renderType(expr ast.Expr, imports map[string]string, set *importSet) (typeRef, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return typeRef{key: t.Name, expr: t.Name}, nil
+	case *ast.ParenExpr:
+		return renderType(t.X, imports, set)
+	case *ast.StarExpr:
+		elem, err := renderType(t.X, imports, set)
+		return typeRef{key: "*" + elem.key, expr: "*" + elem.expr}, err
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		pkgPath, ok := imports[pkg.Name]
+		if !ok {
+			return typeRef{}, fmt.Errorf("unknown package %s", pkg.Name)
+		}
+		name, err := set.use(pkg.Name, pkgPath)
+		if err != nil {
+			return typeRef{}, err
+		}
+		return typeRef{key: pkgPath + "." + t.Sel.Name, expr: name + "." + t.Sel.Name}, nil
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		elem, err := renderType(t.Elt, imports, set)
+		return typeRef{key: "[]" + elem.key, expr: "[]" + elem.expr}, err
+	case *ast.MapType:
+		key, err := renderType(t.Key, imports, set)
+		if err != nil {
+			return typeRef{}, err
+		}
+		value, err := renderType(t.Value, imports, set)
+		return typeRef{key: "map[" + key.key + "]" + value.key, expr: "map[" + key.expr + "]" + value.expr}, err
+	case *ast.IndexExpr:
+		return renderGeneric(t.X, []ast.Expr{t.Index}, imports, set)
+	case *ast.IndexListExpr:
+		return renderGeneric(t.X, t.Indices, imports, set)
+	case *ast.InterfaceType:
+		if len(t.Methods.List) == 0 {
+			return typeRef{key: "any", expr: "any"}, nil
+		}
+	}
+	return typeRef{}, fmt.Errorf("unsupported type %s", exprString(expr))
+}
+
+// renderGeneric 渲染泛型类型实例化 X[A, B]
+func renderGeneric(x ast.Expr, args []ast.Expr, imports map[string]string, set *importSet) (typeRef, error) {
+	base, err := renderType(x, imports, set)
+	if err != nil {
+		return typeRef{}, err
+	}
+	keys := make([]string, len(args))
+	exprs := make([]string, len(args))
+	for i, arg := range args {
+		r, err := renderType(arg, imports, set)
+		if err != nil {
+			return typeRef{}, err
+		}
+		keys[i], exprs[i] = r.key, r.expr
+	}
+	return typeRef{
+		key:  base.key + "[" + strings.Join(keys, ", ") + "]",
+		expr: base.expr + "[" + strings.Join(exprs, ", ") + "]",
+	}, nil
+}
+
+func exprString(expr ast.Expr) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, token.NewFileSet(), expr)
+	return b.String()
+}
+
+// shortPos 返回 file.go:line 形式的位置
+func shortPos(pos token.Position) string {
+	return fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+}
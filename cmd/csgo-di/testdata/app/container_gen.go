@@ -0,0 +1,98 @@
+// Code generated by csgo-di. DO NOT EDIT.
+
+package app
+
+import (
+	"reflect"
+
+	"github.com/gocrud/csgo/config"
+	"github.com/gocrud/csgo/di"
+)
+
+// Container 是由 csgo-di 生成的服务容器，实现 di.IServiceProvider。
+// 服务通过直接调用构造函数创建，di.Get[T] 等 API 可以照常使用。
+type Container struct {
+	*di.StaticProvider
+}
+
+// NewContainer 创建容器，并按注册顺序提前创建所有单例（失败时 panic）。
+func NewContainer() *Container {
+	return &Container{StaticProvider: di.NewStaticProvider(5, []di.StaticService{
+		{Type: reflect.TypeFor[*Options](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return resolveNewOptions(s) }},
+		{Type: reflect.TypeFor[config.IConfiguration](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return resolveNewSettings(s) }},
+		{Type: reflect.TypeFor[*UserRepository](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return resolveNewUserRepository(s) }},
+		{Type: reflect.TypeFor[IUserReader](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) {
+			v, err := resolveNewUserRepository(s)
+			var service IUserReader = v
+			return service, err
+		}},
+		{Type: reflect.TypeFor[*RequestContext](), Lifetime: di.Scoped, Resolve: func(s *di.StaticScope) (any, error) { return resolveNewRequestContext(s) }},
+		{Type: reflect.TypeFor[*Handler](), Lifetime: di.Transient, Resolve: func(s *di.StaticScope) (any, error) { return resolveNewHandler(s) }},
+	})}
+}
+
+// Options 解析 *Options。
+func (c *Container) Options() *Options {
+	return di.StaticMust(resolveNewOptions(c.Scope()))
+}
+
+// IConfiguration 解析 config.IConfiguration。
+func (c *Container) IConfiguration() config.IConfiguration {
+	return di.StaticMust(resolveNewSettings(c.Scope()))
+}
+
+// UserRepository 解析 *UserRepository。
+func (c *Container) UserRepository() *UserRepository {
+	return di.StaticMust(resolveNewUserRepository(c.Scope()))
+}
+
+// Handler 解析 *Handler。
+func (c *Container) Handler() *Handler {
+	return di.StaticMust(resolveNewHandler(c.Scope()))
+}
+
+func resolveNewOptions(s *di.StaticScope) (*Options, error) {
+	return di.StaticSingleton(s, 0, func() (v *Options, err error) {
+		return NewOptions(), nil
+	})
+}
+
+func resolveNewSettings(s *di.StaticScope) (config.IConfiguration, error) {
+	return di.StaticSingleton(s, 1, func() (v config.IConfiguration, err error) {
+		return NewSettings(), nil
+	})
+}
+
+func resolveNewUserRepository(s *di.StaticScope) (*UserRepository, error) {
+	return di.StaticSingleton(s, 2, func() (v *UserRepository, err error) {
+		p0, err := resolveNewOptions(s)
+		if err != nil {
+			return v, err
+		}
+		p1, err := resolveNewSettings(s)
+		if err != nil {
+			return v, err
+		}
+		return NewUserRepository(p0, p1)
+	})
+}
+
+func resolveNewRequestContext(s *di.StaticScope) (*RequestContext, error) {
+	return di.StaticScoped(s, 3, func() (v *RequestContext, err error) {
+		p0, err := resolveNewUserRepository(s)
+		if err != nil {
+			return v, err
+		}
+		return NewRequestContext(p0), nil
+	})
+}
+
+func resolveNewHandler(s *di.StaticScope) (*Handler, error) {
+	return di.StaticTransient(s, func() (v *Handler, err error) {
+		p0, err := resolveNewRequestContext(s)
+		if err != nil {
+			return v, err
+		}
+		return NewHandler(p0), nil
+	})
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/gocrud/csgo/config"
+)
+
+// Options 应用配置
+type Options struct {
+	DSN string
+}
+
+//csgo:inject
+func NewOptions() *Options {
+	return &Options{DSN: "memory"}
+}
+
+//csgo:inject
+func NewSettings() config.IConfiguration {
+	return config.NewConfigurationManager()
+}
+
+// IUserReader 读取用户
+type IUserReader interface {
+	Name(id int) string
+}
+
+// UserRepository 用户仓储
+type UserRepository struct {
+	opts   *Options
+	closed bool
+}
+
+func (r *UserRepository) Name(id int) string { return r.opts.DSN }
+
+func (r *UserRepository) Dispose() error {
+	r.closed = true
+	return nil
+}
+
+//csgo:inject as=IUserReader
+func NewUserRepository(opts *Options, _ config.IConfiguration) (*UserRepository, error) {
+	if opts.DSN == "" {
+		return nil, errors.New("dsn is required")
+	}
+	return &UserRepository{opts: opts}, nil
+}
+
+// RequestContext 请求上下文
+type RequestContext struct {
+	Users IUserReader
+}
+
+//csgo:inject scoped
+func NewRequestContext(users IUserReader) *RequestContext {
+	return &RequestContext{Users: users}
+}
+
+// Handler 请求处理器
+type Handler struct {
+	Request *RequestContext
+}
+
+//csgo:inject transient
+func NewHandler(request *RequestContext) *Handler {
+	return &Handler{Request: request}
+}
@@ -61,7 +61,7 @@ func GetOr[T any](provider IServiceProvider, defaultValue T) T {
 }
 
 // GetAll retrieves all services of the specified type.
-// Returns a slice of all registered services matching the type.
+// Returns a slice of all registered services matching the type, in registration order.
 //   - plugins := di.GetAll[IPlugin](provider)
 func GetAll[T any](provider IServiceProvider) []T {
 	// Use reflect.TypeOf with pointer trick to handle interface types correctly
@@ -0,0 +1,126 @@
+package di_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type ChildLogger struct{ disposed bool }
+type ChildOptions struct{ Tenant string }
+type ChildPool struct {
+	Options  *ChildOptions
+	Logger   *ChildLogger
+	disposed bool
+}
+type ChildRequest struct{ disposed *[]string }
+type IChildMetric interface{ Name() string }
+type ChildMetric struct{ name string }
+
+func (l *ChildLogger) Dispose() error  { l.disposed = true; return nil }
+func (p *ChildPool) Dispose() error    { p.disposed = true; return nil }
+func (r *ChildRequest) Dispose() error { *r.disposed = append(*r.disposed, "request"); return nil }
+func (m ChildMetric) Name() string     { return m.name }
+
+func newChildParent() di.IServiceProvider {
+	services := di.NewServiceCollection()
+	services.Add(func() *ChildLogger { return &ChildLogger{} })
+	services.Add(func() *ChildOptions { return &ChildOptions{Tenant: "default"} })
+	services.Add(func() IChildMetric { return ChildMetric{name: "requests"} })
+	return di.BuildServiceProvider(services)
+}
+
+// TestCreateChildOverridesAndFallsBack tests that child registrations win and other services come from the parent
+func TestCreateChildOverridesAndFallsBack(t *testing.T) {
+	parent := newChildParent()
+	child := parent.CreateChild(func(services di.IServiceCollection) {
+		services.AddInstance(&ChildOptions{Tenant: "acme"})
+		services.Add(func(o *ChildOptions, l *ChildLogger) *ChildPool { return &ChildPool{Options: o, Logger: l} })
+	})
+
+	pool := di.Get[*ChildPool](child)
+	if pool.Options.Tenant != "acme" {
+		t.Errorf("expected the child options to override the parent, got %q", pool.Options.Tenant)
+	}
+	if pool.Logger != di.Get[*ChildLogger](parent) {
+		t.Error("expected the child to share the parent logger singleton")
+	}
+	if di.Get[*ChildOptions](parent).Tenant != "default" {
+		t.Error("expected the parent registrations to be unaffected")
+	}
+	if _, ok := di.TryGet[*ChildPool](parent); ok {
+		t.Error("expected child registrations to be invisible to the parent")
+	}
+	if metrics := di.GetAll[IChildMetric](child); len(metrics) != 1 || metrics[0].Name() != "requests" {
+		t.Errorf("expected GetAll to fall back to the parent, got %v", metrics)
+	}
+}
+
+// TestCreateChildGroupOverride tests that a child registration for a type replaces the whole parent group
+func TestCreateChildGroupOverride(t *testing.T) {
+	child := newChildParent().CreateChild(func(services di.IServiceCollection) {
+		services.Add(func() IChildMetric { return ChildMetric{name: "tenant"} })
+	})
+
+	if metrics := di.GetAll[IChildMetric](child); len(metrics) != 1 || metrics[0].Name() != "tenant" {
+		t.Errorf("expected only the child metric, got %v", metrics)
+	}
+}
+
+// TestCreateChildDisposeOwnInstances tests that disposing a child leaves parent singletons alive
+func TestCreateChildDisposeOwnInstances(t *testing.T) {
+	parent := newChildParent()
+	child := parent.CreateChild(func(services di.IServiceCollection) {
+		services.Add(func(l *ChildLogger) *ChildPool { return &ChildPool{Logger: l} })
+	})
+	pool := di.Get[*ChildPool](child)
+
+	if err := child.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+	if !pool.disposed {
+		t.Error("expected the child pool to be disposed")
+	}
+	if pool.Logger.disposed {
+		t.Error("expected the parent logger to survive child disposal")
+	}
+	if di.Get[*ChildLogger](parent) != pool.Logger {
+		t.Error("expected the parent to keep working after child disposal")
+	}
+}
+
+// TestCreateChildScopedFallback tests that parent scoped services resolved in a child scope live in that scope
+func TestCreateChildScopedFallback(t *testing.T) {
+	var disposed []string
+	services := di.NewServiceCollection()
+	services.AddScoped(func() *ChildRequest { return &ChildRequest{disposed: &disposed} })
+	parent := di.BuildServiceProvider(services)
+	child := parent.CreateChild(nil)
+
+	scope := child.CreateScope()
+	sp := scope.ServiceProvider()
+	if di.Get[*ChildRequest](sp) != di.Get[*ChildRequest](sp) {
+		t.Error("expected one scoped instance per child scope")
+	}
+	if err := scope.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(disposed, ",") != "request" {
+		t.Errorf("expected the child scope to dispose the parent scoped service, got %v", disposed)
+	}
+}
+
+// TestCreateChildValidateOnBuild tests that dependencies satisfied by the parent pass validation
+func TestCreateChildValidateOnBuild(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(func() *ChildLogger { return &ChildLogger{} })
+	parent := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ValidateOnBuild: true})
+
+	child := parent.CreateChild(func(services di.IServiceCollection) {
+		services.Add(func(l *ChildLogger) *ChildPool { return &ChildPool{Logger: l} })
+	})
+	if di.Get[*ChildPool](child).Logger == nil {
+		t.Error("expected the parent dependency to be injected")
+	}
+}
@@ -0,0 +1,105 @@
+package di_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type conditionContext struct {
+	env    string
+	config map[string]string
+}
+
+func (c *conditionContext) EnvironmentName() string     { return c.env }
+func (c *conditionContext) GetConfig(key string) string { return c.config[key] }
+
+type ICondCache interface{ Name() string }
+type CondMemoryCache struct{}
+type CondRedisCache struct{}
+type CondFakeMailer struct{}
+
+func (CondMemoryCache) Name() string { return "memory" }
+func (CondRedisCache) Name() string  { return "redis" }
+
+// TestAddWhenConfigUsesFinalConfiguration tests that config conditions are evaluated at build time
+func TestAddWhenConfigUsesFinalConfiguration(t *testing.T) {
+	ctx := &conditionContext{env: "production", config: map[string]string{}}
+	services := di.NewServiceCollection()
+	di.SetConditionContext(services, ctx)
+
+	services.Add(func() ICondCache { return CondMemoryCache{} })
+	services.AddWhenConfig("Features:Cache:Enabled", func() ICondCache { return CondRedisCache{} })
+
+	// Configuration changes after registration are still seen by the condition
+	ctx.config["Features:Cache:Enabled"] = "true"
+	provider := di.BuildServiceProvider(services)
+
+	if got := di.Get[ICondCache](provider).Name(); got != "redis" {
+		t.Errorf("expected the conditional registration to win, got %s", got)
+	}
+	if n := len(di.GetAll[ICondCache](provider)); n != 2 {
+		t.Errorf("expected 2 registrations, got %d", n)
+	}
+}
+
+// TestAddWhenConfigFalseDropsRegistration tests that a false or missing config value skips the registration
+func TestAddWhenConfigFalseDropsRegistration(t *testing.T) {
+	for _, value := range []string{"", "false", "nope"} {
+		services := di.NewServiceCollection()
+		di.SetConditionContext(services, &conditionContext{config: map[string]string{"Features:Cache:Enabled": value}})
+
+		services.Add(func() ICondCache { return CondMemoryCache{} })
+		services.AddWhenConfig("Features:Cache:Enabled", func() ICondCache { return CondRedisCache{} })
+		provider := di.BuildServiceProvider(services)
+
+		if got := di.GetAll[ICondCache](provider); len(got) != 1 || got[0].Name() != "memory" {
+			t.Errorf("value %q: expected only the memory cache, got %v", value, got)
+		}
+	}
+}
+
+// TestAddWhenEnvironment tests that environment conditions match case-insensitively
+func TestAddWhenEnvironment(t *testing.T) {
+	build := func(env string) di.IServiceProvider {
+		services := di.NewServiceCollection()
+		di.SetConditionContext(services, &conditionContext{env: env})
+		services.AddWhenEnvironment("Development", func() *CondFakeMailer { return &CondFakeMailer{} })
+		return di.BuildServiceProvider(services)
+	}
+
+	if _, ok := di.TryGet[*CondFakeMailer](build("development")); !ok {
+		t.Error("expected the service to be registered in development")
+	}
+	if _, ok := di.TryGet[*CondFakeMailer](build("production")); ok {
+		t.Error("expected the service to be skipped in production")
+	}
+}
+
+// TestAddIf tests that custom conditions receive the condition context
+func TestAddIf(t *testing.T) {
+	services := di.NewServiceCollection()
+	di.SetConditionContext(services, &conditionContext{env: "staging", config: map[string]string{"Mail:Provider": "fake"}})
+	services.AddIf(func(ctx di.ConditionContext) bool {
+		return ctx.EnvironmentName() != "production" && ctx.GetConfig("Mail:Provider") == "fake"
+	}, func() *CondFakeMailer { return &CondFakeMailer{} })
+
+	if _, ok := di.TryGet[*CondFakeMailer](di.BuildServiceProvider(services)); !ok {
+		t.Error("expected the custom condition to register the service")
+	}
+}
+
+// TestConditionWithoutContext tests that building fails when no condition context is set
+func TestConditionWithoutContext(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddWhenEnvironment("development", func() *CondFakeMailer { return &CondFakeMailer{} })
+
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "no condition context") {
+			t.Errorf("expected a missing condition context error, got %q", msg)
+		}
+	}()
+	di.BuildServiceProvider(services)
+}
@@ -0,0 +1,139 @@
+package di_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type IGreeter interface {
+	Greet() string
+}
+
+type greeter struct {
+	disposed bool
+}
+
+func (g *greeter) Greet() string  { return "hello" }
+func (g *greeter) Dispose() error { g.disposed = true; return nil }
+
+type suffixGreeter struct {
+	inner  IGreeter
+	suffix string
+}
+
+func (g *suffixGreeter) Greet() string { return g.inner.Greet() + g.suffix }
+
+type Punctuation struct {
+	Mark string
+}
+
+// TestDecorateStacksInRegistrationOrder tests that decorators wrap in registration order
+func TestDecorateStacksInRegistrationOrder(t *testing.T) {
+	services := di.NewServiceCollection()
+	di.Decorate[IGreeter](services, func(inner IGreeter) IGreeter {
+		return &suffixGreeter{inner: inner, suffix: " world"}
+	})
+	services.Add(func() IGreeter { return &greeter{} })
+	services.Add(func() *Punctuation { return &Punctuation{Mark: "!"} })
+	di.Decorate[IGreeter](services, func(inner IGreeter, p *Punctuation) IGreeter {
+		return &suffixGreeter{inner: inner, suffix: p.Mark}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	g := di.Get[IGreeter](provider)
+	if got := g.Greet(); got != "hello world!" {
+		t.Errorf("Expected 'hello world!', got '%s'", got)
+	}
+	if di.Get[IGreeter](provider) != g {
+		t.Error("Expected decorated singleton to be cached")
+	}
+}
+
+// TestDecorateKeepsOriginalForDisposal tests that the undecorated instance is still disposed
+func TestDecorateKeepsOriginalForDisposal(t *testing.T) {
+	original := &greeter{}
+	services := di.NewServiceCollection()
+	services.Add(func() IGreeter { return original })
+	services.Decorate(func(inner IGreeter) IGreeter {
+		return &suffixGreeter{inner: inner}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	if _, ok := di.Get[IGreeter](provider).(*suffixGreeter); !ok {
+		t.Fatal("Expected the decorator output to be resolved")
+	}
+	provider.Dispose()
+	if !original.disposed {
+		t.Error("Expected the original instance to be disposed")
+	}
+}
+
+// TestDecorateScoped tests that decorators follow the lifetime of the decorated service
+func TestDecorateScoped(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddScoped(func() IGreeter { return &greeter{} })
+	services.Decorate(func(inner IGreeter) IGreeter {
+		return &suffixGreeter{inner: inner, suffix: "?"}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	scope := provider.CreateScope()
+	defer scope.Dispose()
+
+	a := di.Get[IGreeter](scope.ServiceProvider())
+	if a != di.Get[IGreeter](scope.ServiceProvider()) {
+		t.Error("Expected one decorated instance per scope")
+	}
+	if a == di.Get[IGreeter](provider.CreateScope().ServiceProvider()) {
+		t.Error("Expected different decorated instances across scopes")
+	}
+}
+
+// TestDecorateMissingService tests that decorating an unregistered service fails at build
+func TestDecorateMissingService(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Decorate(func(inner IGreeter) IGreeter { return inner })
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for decorating an unregistered service")
+		}
+		if errStr := r.(string); !strings.Contains(errStr, "cannot decorate") {
+			t.Errorf("Unexpected error: %s", errStr)
+		}
+	}()
+
+	di.BuildServiceProvider(services)
+}
+
+// TestDecorateMissingServicesInOrder tests that missing decorated services are reported in decoration order
+func TestDecorateMissingServicesInOrder(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		services := di.NewServiceCollection()
+		services.Decorate(func(inner IGreeter) IGreeter { return inner })
+		services.Decorate(func(inner *Punctuation) *Punctuation { return inner })
+		services.Decorate(func(inner *greeter) *greeter { return inner })
+		services.Decorate(func(inner *suffixGreeter) *suffixGreeter { return inner })
+
+		errStr := func() (msg string) {
+			defer func() { msg, _ = recover().(string) }()
+			di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ValidateOnBuild: true})
+			return ""
+		}()
+
+		positions := []int{
+			strings.Index(errStr, "cannot decorate service 'github.com/gocrud/csgo/di_test.IGreeter'"),
+			strings.Index(errStr, "cannot decorate service '*github.com/gocrud/csgo/di_test.Punctuation'"),
+			strings.Index(errStr, "cannot decorate service '*github.com/gocrud/csgo/di_test.greeter'"),
+			strings.Index(errStr, "cannot decorate service '*github.com/gocrud/csgo/di_test.suffixGreeter'"),
+		}
+		for j, pos := range positions {
+			if pos < 0 || (j > 0 && pos < positions[j-1]) {
+				t.Fatalf("Expected errors in decoration order, got:\n%s", errStr)
+			}
+		}
+	}
+}
@@ -17,6 +17,9 @@ type IDisposable interface {
 // IAsyncDisposable defines an interface for resources that require asynchronous cleanup.
 // This is useful for services that need to perform I/O operations during disposal,
 // such as flushing buffers or gracefully closing network connections.
+// When a service implements both interfaces, DisposeAsync is called instead of Dispose.
+// IServiceProvider.Dispose calls it with context.Background(); use
+// IServiceProvider.DisposeAsync to bound cleanup with a deadline.
 //
 // Corresponds to .NET's IAsyncDisposable interface.
 type IAsyncDisposable interface {
@@ -0,0 +1,143 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type disposeLog struct {
+	order []string
+}
+
+type DisposeConsumer struct {
+	log *disposeLog
+}
+
+func (c *DisposeConsumer) Dispose() error {
+	c.log.order = append(c.log.order, "consumer")
+	return nil
+}
+
+type DisposeConnection struct {
+	log   *disposeLog
+	block bool
+}
+
+func (c *DisposeConnection) Dispose() error {
+	c.log.order = append(c.log.order, "connection.Dispose")
+	return nil
+}
+
+func (c *DisposeConnection) DisposeAsync(ctx context.Context) error {
+	if c.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	c.log.order = append(c.log.order, "connection")
+	return nil
+}
+
+type DisposeCache struct {
+	log *disposeLog
+}
+
+func (c *DisposeCache) Dispose() error {
+	c.log.order = append(c.log.order, "cache")
+	return nil
+}
+
+func newDisposeServices(log *disposeLog, block bool) di.IServiceCollection {
+	services := di.NewServiceCollection()
+	services.AddInstance(log)
+	// Registered before its dependencies, so registration order differs from construction order
+	services.Add(func(l *disposeLog, _ *DisposeConnection) *DisposeConsumer { return &DisposeConsumer{log: l} })
+	services.Add(func(l *disposeLog, _ *DisposeCache) *DisposeConnection {
+		return &DisposeConnection{log: l, block: block}
+	})
+	services.Add(func(l *disposeLog) *DisposeCache { return &DisposeCache{log: l} })
+	return services
+}
+
+// TestDisposeAsyncReverseConstructionOrder tests that services are disposed after everything depending on them
+func TestDisposeAsyncReverseConstructionOrder(t *testing.T) {
+	log := &disposeLog{}
+	provider := di.BuildServiceProvider(newDisposeServices(log, false))
+
+	if err := provider.DisposeAsync(context.Background()); err != nil {
+		t.Fatalf("dispose failed: %v", err)
+	}
+
+	// IAsyncDisposable is preferred over IDisposable
+	if got := strings.Join(log.order, ","); got != "consumer,connection,cache" {
+		t.Errorf("expected consumer,connection,cache, got %s", got)
+	}
+}
+
+// TestDisposeAsyncReportsTimedOutServices tests that disposal stops at the deadline and reports what was left
+func TestDisposeAsyncReportsTimedOutServices(t *testing.T) {
+	log := &disposeLog{}
+	provider := di.BuildServiceProvider(newDisposeServices(log, true))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := provider.DisposeAsync(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	var timeout *di.DisposeTimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("expected DisposeTimeoutError, got %T", err)
+	}
+	expected := []string{"*di_test.DisposeConnection", "*di_test.DisposeCache"}
+	if strings.Join(timeout.Services, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected timed out services %v, got %v", expected, timeout.Services)
+	}
+	if got := strings.Join(log.order, ","); got != "consumer" {
+		t.Errorf("expected only consumer to be disposed, got %s", got)
+	}
+}
+
+// TestScopeDisposeAsync tests that scoped services support async disposal
+func TestScopeDisposeAsync(t *testing.T) {
+	log := &disposeLog{}
+	services := di.NewServiceCollection()
+	services.AddInstance(log)
+	services.AddScoped(func(l *disposeLog) *DisposeConnection { return &DisposeConnection{log: l} })
+	provider := di.BuildServiceProvider(services)
+
+	scope := provider.CreateScope()
+	di.Get[*DisposeConnection](scope.ServiceProvider())
+	if err := scope.DisposeAsync(context.Background()); err != nil {
+		t.Fatalf("dispose failed: %v", err)
+	}
+	if got := strings.Join(log.order, ","); got != "connection" {
+		t.Errorf("expected async disposal of the scoped service, got %s", got)
+	}
+}
+
+// TestDisposeSharedInstanceOnce tests that an instance reachable through several registrations is disposed once
+func TestDisposeSharedInstanceOnce(t *testing.T) {
+	log := &disposeLog{}
+	cache := &DisposeCache{log: log}
+	services := di.NewServiceCollection()
+	services.AddInstance(cache)
+	services.AddInstance(cache)
+	services.Add(func() *DisposeConsumer { return &DisposeConsumer{log: log} })
+	di.Decorate[*DisposeConsumer](services, func(inner *DisposeConsumer) *DisposeConsumer { return inner })
+	provider := di.BuildServiceProvider(services)
+	di.Get[*DisposeConsumer](provider)
+
+	if err := provider.Dispose(); err != nil {
+		t.Fatalf("dispose failed: %v", err)
+	}
+	if got := strings.Join(log.order, ","); got != "consumer,cache" {
+		t.Errorf("expected each instance to be disposed once, got %s", got)
+	}
+}
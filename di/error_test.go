@@ -103,4 +103,40 @@ func TestErrorReporting(t *testing.T) {
 
 		di.BuildServiceProvider(services)
 	})
+
+	// 3. Captive Dependency Test
+	t.Run("CaptiveDependency", func(t *testing.T) {
+		type RequestState struct{}
+		type Cache struct{}
+
+		for name, register := range map[string]func(di.IServiceCollection){
+			"Scoped":    func(s di.IServiceCollection) { s.AddScoped(func() *RequestState { return &RequestState{} }) },
+			"Transient": func(s di.IServiceCollection) { s.AddTransient(func() *RequestState { return &RequestState{} }) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				services := di.NewServiceCollection()
+				register(services)
+				services.Add(func(r *RequestState) *Cache { return &Cache{} })
+
+				defer func() {
+					r := recover()
+					if r == nil {
+						t.Fatal("Expected panic for captive dependency during build")
+					}
+
+					errStr := r.(string)
+					if !strings.Contains(errStr, "captive dependency detected") {
+						t.Errorf("Unexpected error: %s", errStr)
+					}
+					if !strings.Contains(errStr, "└─") || !strings.Contains(errStr, "❌") {
+						t.Errorf("Error message missing tree structure: %s", errStr)
+					}
+
+					t.Logf("Got expected captive dependency error:\n%s", errStr)
+				}()
+
+				di.BuildServiceProvider(services)
+			})
+		}
+	})
 }
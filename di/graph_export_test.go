@@ -0,0 +1,114 @@
+package di_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type graphCache struct{}
+
+type graphStore struct {
+	Cache *graphCache
+}
+
+type graphHandler struct {
+	Store *graphStore
+}
+
+type graphParams struct {
+	di.In
+	Store   *graphStore
+	Metrics *graphMetrics `di:"optional"`
+}
+
+type graphMetrics struct{}
+
+func newGraphProvider() di.IServiceProvider {
+	services := di.NewServiceCollection()
+	services.Add(func() *graphCache { return &graphCache{} })
+	services.AddScoped(func(c *graphCache) *graphStore { return &graphStore{Cache: c} })
+	services.AddTransient(func(p graphParams) *graphHandler { return &graphHandler{Store: p.Store} })
+	return di.BuildServiceProvider(services)
+}
+
+// TestExportGraphJSON tests that nodes carry lifetimes and edges follow dependencies
+func TestExportGraphJSON(t *testing.T) {
+	out, err := di.ExportGraph(newGraphProvider(), di.GraphFormatJSON)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	var graph struct {
+		Nodes []struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Lifetime string `json:"lifetime"`
+			Missing  bool   `json:"missing"`
+		} `json:"nodes"`
+		Edges []struct {
+			From     string `json:"from"`
+			To       string `json:"to"`
+			Optional bool   `json:"optional"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal([]byte(out), &graph); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+
+	ids := map[string]string{}
+	lifetimes := map[string]string{}
+	for _, n := range graph.Nodes {
+		short := n.Name[strings.LastIndex(n.Name, ".")+1:]
+		ids[n.ID] = short
+		lifetimes[short] = n.Lifetime
+	}
+	want := map[string]string{"graphCache": "Singleton", "graphStore": "Scoped", "graphHandler": "Transient", "graphMetrics": ""}
+	for name, lifetime := range want {
+		if got, ok := lifetimes[name]; !ok || got != lifetime {
+			t.Errorf("node %s: expected lifetime %q, got %q (present=%v)", name, lifetime, got, ok)
+		}
+	}
+
+	var edges []string
+	for _, e := range graph.Edges {
+		edge := ids[e.From] + "->" + ids[e.To]
+		if e.Optional {
+			edge += "?"
+		}
+		edges = append(edges, edge)
+	}
+	expected := "graphStore->graphCache,graphHandler->graphStore,graphHandler->graphMetrics?"
+	if strings.Join(edges, ",") != expected {
+		t.Errorf("expected edges %s, got %s", expected, strings.Join(edges, ","))
+	}
+}
+
+// TestExportGraphDOTAndMermaid tests the text formats
+func TestExportGraphDOTAndMermaid(t *testing.T) {
+	provider := newGraphProvider()
+
+	dot, err := di.ExportGraph(provider, di.GraphFormatDOT)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if !strings.HasPrefix(dot, "digraph services {") || !strings.Contains(dot, `graphStore\n(Scoped)"`) ||
+		!strings.Contains(dot, "n1 -> n0;") {
+		t.Errorf("unexpected DOT output:\n%s", dot)
+	}
+
+	mermaid, err := di.ExportGraph(provider, di.GraphFormatMermaid)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if !strings.HasPrefix(mermaid, "flowchart LR") || !strings.Contains(mermaid, "graphCache<br/>(Singleton)") ||
+		!strings.Contains(mermaid, "n2 -.->|optional| n3") {
+		t.Errorf("unexpected Mermaid output:\n%s", mermaid)
+	}
+
+	if _, err := di.ExportGraph(provider, "svg"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
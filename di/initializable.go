@@ -0,0 +1,66 @@
+package di
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocrud/csgo/di/internal"
+)
+
+// IInitializable defines an interface for singletons that need context-aware or
+// asynchronous warm-up after construction, such as opening connections or priming caches.
+// Constructors stay cheap and synchronous; the work that needs a context goes here.
+//
+// The host calls Initialize on every created singleton before any IHostedService starts,
+// in dependency order (a service is initialized after everything it depends on).
+// A failure aborts startup.
+type IInitializable interface {
+	// Initialize prepares the service for use.
+	// The context is bounded by the host's startup timeout.
+	Initialize(ctx context.Context) error
+}
+
+// InitializeSingletons calls IInitializable.Initialize on every singleton created so far,
+// in dependency order, stopping at the first error or when ctx expires.
+// The error shows the failing service in the same dependency-tree format as resolution errors:
+//
+//	failed to initialize service '*app.Cache':
+//	  └─ *app.UserController
+//	     └─ *app.UserService
+//	        └─ ❌ *app.Cache
+//	  Cause: dial tcp: connection refused
+//
+// The host calls this automatically. With ServiceProviderOptions.LazySingletons, only
+// singletons that were resolved before the call are initialized.
+// Providers other than those built by BuildServiceProvider and NewStaticProvider have
+// no singletons to initialize, and the call returns nil.
+func InitializeSingletons(ctx context.Context, provider IServiceProvider) error {
+	if initializer, ok := provider.(singletonInitializer); ok {
+		return initializer.initializeSingletons(ctx)
+	}
+	return nil
+}
+
+// singletonInitializer is implemented by the providers that track the singletons they create.
+type singletonInitializer interface {
+	initializeSingletons(ctx context.Context) error
+}
+
+func (p *serviceProvider) initializeSingletons(ctx context.Context) error {
+	return p.engine.InitializeSingletons(ctx)
+}
+
+// initializeSingletons initializes the singletons in creation order; a singleton is created
+// after its dependencies, and one exposed under several types is created once.
+func (p *StaticProvider) initializeSingletons(ctx context.Context) error {
+	for _, instance := range p.scope.snapshot() {
+		init, ok := instance.(IInitializable)
+		if !ok {
+			continue
+		}
+		if err := internal.InitializeInstance(ctx, init); err != nil {
+			return fmt.Errorf("failed to initialize service '%T': %w", instance, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type initLog struct {
+	order []string
+}
+
+type InitController struct{ log *initLog }
+type InitService struct{ log *initLog }
+type InitCache struct {
+	log  *initLog
+	err  error
+	wait bool
+}
+
+func (c *InitController) Initialize(ctx context.Context) error {
+	c.log.order = append(c.log.order, "controller")
+	return nil
+}
+
+func (s *InitService) Initialize(ctx context.Context) error {
+	s.log.order = append(s.log.order, "service")
+	return nil
+}
+
+func (c *InitCache) Initialize(ctx context.Context) error {
+	if c.wait {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	c.log.order = append(c.log.order, "cache")
+	return c.err
+}
+
+func newInitServices(log *initLog, cache *InitCache) di.IServiceCollection {
+	services := di.NewServiceCollection()
+	services.AddInstance(log)
+	// Registered in reverse dependency order
+	services.Add(func(l *initLog, _ *InitService) *InitController { return &InitController{log: l} })
+	services.Add(func(l *initLog, _ *InitCache) *InitService { return &InitService{log: l} })
+	services.AddInstance(cache)
+	return services
+}
+
+// TestInitializeSingletonsDependencyOrder tests that dependencies are initialized first
+func TestInitializeSingletonsDependencyOrder(t *testing.T) {
+	log := &initLog{}
+	provider := di.BuildServiceProvider(newInitServices(log, &InitCache{log: log}))
+
+	if err := di.InitializeSingletons(context.Background(), provider); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if got := strings.Join(log.order, ","); got != "cache,service,controller" {
+		t.Errorf("expected cache,service,controller, got %s", got)
+	}
+}
+
+// TestInitializeSingletonsFailureTree tests that a failure stops initialization and reports a dependency tree
+func TestInitializeSingletonsFailureTree(t *testing.T) {
+	log := &initLog{}
+	cause := errors.New("connection refused")
+	provider := di.BuildServiceProvider(newInitServices(log, &InitCache{log: log, err: cause}))
+
+	err := di.InitializeSingletons(context.Background(), provider)
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected wrapped cause, got %v", err)
+	}
+	for _, expected := range []string{
+		"failed to initialize service '*github.com/gocrud/csgo/di_test.InitCache'",
+		"└─ *github.com/gocrud/csgo/di_test.InitController",
+		"└─ *github.com/gocrud/csgo/di_test.InitService",
+		"└─ ❌ *github.com/gocrud/csgo/di_test.InitCache",
+		"Cause: connection refused",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q:\n%v", expected, err)
+		}
+	}
+	if len(log.order) != 1 {
+		t.Errorf("expected initialization to stop after the failure, got %v", log.order)
+	}
+}
+
+// TestInitializeSingletonsTimeout tests that initialization is bounded by the context
+func TestInitializeSingletonsTimeout(t *testing.T) {
+	log := &initLog{}
+	provider := di.BuildServiceProvider(newInitServices(log, &InitCache{log: log, wait: true}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := di.InitializeSingletons(ctx, provider)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// TestInitializeSingletonsStaticProvider tests that generated containers initialize singletons in creation order
+func TestInitializeSingletonsStaticProvider(t *testing.T) {
+	log := &initLog{}
+	cache := func(s *di.StaticScope) (*InitCache, error) {
+		return di.StaticSingleton(s, 0, func() (*InitCache, error) { return &InitCache{log: log}, nil })
+	}
+	service := func(s *di.StaticScope) (*InitService, error) {
+		return di.StaticSingleton(s, 1, func() (v *InitService, err error) {
+			if _, err = cache(s); err != nil {
+				return v, err
+			}
+			return &InitService{log: log}, nil
+		})
+	}
+	provider := di.NewStaticProvider(2, []di.StaticService{
+		{Type: reflect.TypeFor[*InitService](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return service(s) }},
+		{Type: reflect.TypeFor[*InitCache](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return cache(s) }},
+	})
+
+	if err := di.InitializeSingletons(context.Background(), provider); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if got := strings.Join(log.order, ","); got != "cache,service" {
+		t.Errorf("expected cache,service, got %s", got)
+	}
+}
+
+// wrappedProvider is a provider implemented outside the di package
+type wrappedProvider struct {
+	di.IServiceProvider
+}
+
+// TestInitializeSingletonsUnknownProvider tests that providers without tracked singletons are skipped
+func TestInitializeSingletonsUnknownProvider(t *testing.T) {
+	log := &initLog{}
+	provider := di.BuildServiceProvider(newInitServices(log, &InitCache{log: log}))
+
+	if err := di.InitializeSingletons(context.Background(), wrappedProvider{provider}); err != nil {
+		t.Fatalf("expected unknown providers to be skipped, got %v", err)
+	}
+	if len(log.order) != 0 {
+		t.Errorf("expected nothing to be initialized, got %v", log.order)
+	}
+}
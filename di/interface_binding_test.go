@@ -0,0 +1,81 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type IUserReader interface {
+	Find(id int) string
+}
+
+type IUserWriter interface {
+	Save(id int, name string)
+}
+
+type MemoryUserRepo struct {
+	users map[int]string
+}
+
+func NewMemoryUserRepo() *MemoryUserRepo {
+	return &MemoryUserRepo{users: map[int]string{}}
+}
+
+func (r *MemoryUserRepo) Find(id int) string       { return r.users[id] }
+func (r *MemoryUserRepo) Save(id int, name string) { r.users[id] = name }
+
+type UserHandler struct {
+	Reader IUserReader
+	Writer IUserWriter
+}
+
+// TestAddAsSharesInstance tests that the concrete type and all interfaces share one singleton
+func TestAddAsSharesInstance(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddAs(NewMemoryUserRepo, new(IUserReader), (*IUserWriter)(nil))
+	services.Add(func(r IUserReader, w IUserWriter) *UserHandler {
+		return &UserHandler{Reader: r, Writer: w}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	repo := di.Get[*MemoryUserRepo](provider)
+	reader := di.Get[IUserReader](provider)
+	writer := di.Get[IUserWriter](provider)
+	if reader != IUserReader(repo) || writer != IUserWriter(repo) {
+		t.Fatal("Expected interfaces to resolve to the same instance")
+	}
+
+	handler := di.Get[*UserHandler](provider)
+	handler.Writer.Save(1, "alice")
+	if handler.Reader.Find(1) != "alice" {
+		t.Error("Expected injected interfaces to share state")
+	}
+
+	if all := di.GetAll[IUserReader](provider); len(all) != 1 {
+		t.Errorf("Expected GetAll to return the bound implementation, got %d", len(all))
+	}
+}
+
+// TestAddAsGeneric tests the generic AddAs helper
+func TestAddAsGeneric(t *testing.T) {
+	services := di.NewServiceCollection()
+	di.AddAs[*MemoryUserRepo, IUserReader](services, NewMemoryUserRepo)
+	provider := di.BuildServiceProvider(services)
+
+	if di.Get[IUserReader](provider) != IUserReader(di.Get[*MemoryUserRepo](provider)) {
+		t.Error("Expected interface and concrete type to share the instance")
+	}
+}
+
+// TestAddAsRejectsUnimplementedInterface tests registration-time validation
+func TestAddAsRejectsUnimplementedInterface(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic for an interface the implementation does not satisfy")
+		}
+	}()
+
+	services := di.NewServiceCollection()
+	services.AddAs(NewTestService, new(IUserReader))
+}
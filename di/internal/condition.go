@@ -0,0 +1,30 @@
+package internal
+
+import "fmt"
+
+// applyConditions 求值条件注册，移除条件不成立的注册及其接口别名（调用方已持有锁）
+// 每个注册的条件只求值一次；求值失败时返回第一个错误
+func (e *Engine) applyConditions() error {
+	evaluated := make(map[*Registration]bool)
+	dropped := make(map[*Registration]bool)
+	for _, b := range e.bindings {
+		reg := b.reg
+		if reg.Condition == nil || evaluated[reg] {
+			continue
+		}
+		evaluated[reg] = true
+
+		ok, err := reg.Condition()
+		if err != nil {
+			return fmt.Errorf("failed to evaluate registration condition for %s: %w", formatKey(reg.Key()), err)
+		}
+		if !ok {
+			dropped[reg] = true
+		}
+	}
+
+	if len(dropped) > 0 {
+		e.keepBindings(func(b *binding) bool { return !dropped[b.reg] })
+	}
+	return nil
+}
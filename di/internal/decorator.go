@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Decorate 注册装饰器
+// 装饰器工厂的第一个参数和第一个返回值必须是被装饰的服务类型，其余参数作为依赖解析
+// 同一服务的多个装饰器按注册顺序叠加：先注册的装饰器在内层
+func (e *Engine) Decorate(dec *Registration, serviceKey string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.compiled.Load() {
+		return errors.New("cannot register after compilation")
+	}
+
+	factoryType := reflect.TypeOf(dec.Factory)
+	if factoryType == nil || factoryType.Kind() != reflect.Func {
+		return errors.New("decorator must be a function")
+	}
+	if factoryType.NumIn() == 0 || factoryType.NumOut() == 0 || factoryType.In(0) != factoryType.Out(0) {
+		return errors.New("decorator must take the decorated service as its first parameter and return the same type")
+	}
+
+	if err := prepareRegistration(dec); err != nil {
+		return err
+	}
+
+	// 第一个参数由内层实例填充，不作为依赖解析
+	deps := make([]Dependency, 0, len(dec.Dependencies))
+	for _, d := range dec.Dependencies {
+		if d.Index != 0 {
+			deps = append(deps, d)
+		}
+	}
+	dec.Dependencies = deps
+
+	dec.ServiceKey = serviceKey
+	key := dec.Key()
+	if _, exists := e.decorators[key]; !exists {
+		e.decorated = append(e.decorated, key)
+	}
+	e.decorators[key] = append(e.decorators[key], dec)
+	e.track(dec)
+
+	return nil
+}
+
+// applyDecorators 在编译时把装饰器链接到被装饰的注册上（调用方已持有锁）
+// 装饰后，注册键指向最外层装饰器，每一层通过 Inner 引用内层注册
+// 按装饰顺序返回所有找不到被装饰服务的错误
+func (e *Engine) applyDecorators() []error {
+	var errs []error
+	for _, key := range e.decorated {
+		// 被 RemoveType 移除后又重新装饰的键会出现多次，只处理一次
+		decs, ok := e.decorators[key]
+		if !ok {
+			continue
+		}
+		delete(e.decorators, key)
+
+		inner, exists := e.registrations[key]
+		if !exists {
+			tree := formatDependencyTree(nil, formatKey(key))
+			errs = append(errs, fmt.Errorf("cannot decorate service '%s':%s\n  Cause: service not registered",
+				formatKey(key), tree))
+			continue
+		}
+
+		for _, dec := range decs {
+			dec.Inner = inner
+			dec.Lifetime = inner.Lifetime
+			dec.ImplementationType = inner.ImplementationType
+			inner = dec
+		}
+
+		// 只装饰该键当前生效的注册（最后一个绑定）
+		for i := len(e.bindings) - 1; i >= 0; i-- {
+			if e.bindings[i].key == key {
+				e.bindings[i].reg = inner
+				break
+			}
+		}
+		e.registrations[key] = inner
+	}
+	e.decorated = nil
+	return errs
+}
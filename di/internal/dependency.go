@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// In 参数对象标记
+// 构造函数的结构体参数嵌入 In 后，其每个导出字段都会作为独立依赖从容器解析
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+// Dependency 依赖描述
+type Dependency struct {
+	Key      RegistrationKey // 依赖的注册键（类型 + 名称）；All 为 true 时 Type 为切片元素类型
+	Index    int             // 构造函数参数索引
+	Field    int             // 参数对象中的字段索引，-1 表示整个参数
+	Optional bool            // 未注册时保持零值而不是报错
+	All      bool            // 注入该类型的所有注册（[]T 字段）
+	Lazy     bool            // Lazy[T]：首次调用 Value() 时解析
+	Func     bool            // func() T：每次调用时解析
+}
+
+// Deferred 判断依赖是否延迟解析
+// 延迟依赖不参与构造顺序和循环检测，可以打破合理的循环（例如事件总线与订阅者）
+func (d Dependency) Deferred() bool {
+	return d.Lazy || d.Func
+}
+
+// IsParamObject 判断类型是否为参数对象（嵌入了 In 的结构体）
+func IsParamObject(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeDependencies 分析构造函数参数，展开参数对象
+func analyzeDependencies(inputTypes []reflect.Type) ([]Dependency, error) {
+	deps := make([]Dependency, 0, len(inputTypes))
+	for i, t := range inputTypes {
+		if !IsParamObject(t) {
+			target, lazy, fn := deferredTarget(t)
+			deps = append(deps, Dependency{Key: RegistrationKey{Type: target}, Index: i, Field: -1, Lazy: lazy, Func: fn})
+			continue
+		}
+
+		for j := 0; j < t.NumField(); j++ {
+			f := t.Field(j)
+			if f.Anonymous && f.Type == inType {
+				continue
+			}
+			if !f.IsExported() {
+				return nil, fmt.Errorf("parameter object %s: field '%s' must be exported", formatType(t), f.Name)
+			}
+
+			tag, err := parseInjectTag(f.Tag.Get("di"))
+			if err != nil {
+				return nil, fmt.Errorf("parameter object %s: field '%s': %w", formatType(t), f.Name, err)
+			}
+
+			target, lazy, fn := deferredTarget(f.Type)
+			dep := Dependency{Key: RegistrationKey{Type: target, Name: tag.name}, Index: i, Field: j, Optional: tag.optional, Lazy: lazy, Func: fn}
+			// []T 字段接收 T 的所有注册，与 GetAll 一致
+			if f.Type.Kind() == reflect.Slice {
+				if tag.name != "" {
+					return nil, fmt.Errorf("parameter object %s: field '%s': name is not supported on slice fields", formatType(t), f.Name)
+				}
+				dep.Key = RegistrationKey{Type: f.Type.Elem()}
+				dep.All = true
+			}
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+// paramType 返回依赖对应的参数或参数对象字段的声明类型
+func paramType(inputTypes []reflect.Type, d Dependency) reflect.Type {
+	t := inputTypes[d.Index]
+	if d.Field >= 0 {
+		t = t.Field(d.Field).Type
+	}
+	return t
+}
+
+// injectTag 解析后的 di 结构体标签
+type injectTag struct {
+	name     string
+	optional bool
+}
+
+// parseInjectTag 解析 di 结构体标签，例如 `di:"name=replica,optional"`
+func parseInjectTag(tag string) (injectTag, error) {
+	var result injectTag
+	if tag == "" {
+		return result, nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case part == "optional":
+			result.optional = true
+		case strings.HasPrefix(part, "name="):
+			result.name = strings.TrimPrefix(part, "name=")
+			if result.name == "" {
+				return result, fmt.Errorf("empty name in di tag %q", tag)
+			}
+		default:
+			return result, fmt.Errorf("unknown option %q in di tag %q", part, tag)
+		}
+	}
+	return result, nil
+}
@@ -4,18 +4,39 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // ServiceLifetime 服务生命周期
-// 简化版本只支持 Singleton
 type ServiceLifetime int
 
 const (
 	Singleton ServiceLifetime = iota
+	Scoped
+	Transient
 )
 
+// String 返回生命周期名称
+func (l ServiceLifetime) String() string {
+	switch l {
+	case Singleton:
+		return "Singleton"
+	case Scoped:
+		return "Scoped"
+	case Transient:
+		return "Transient"
+	default:
+		return "Unknown"
+	}
+}
+
+// TypeID 注册的唯一 ID，即注册在 Engine 中的顺序索引
+// 用作 Singleton 缓存和作用域缓存的下标
+type TypeID int32
+
 // RegistrationKey 注册键
 type RegistrationKey struct {
 	Type reflect.Type
@@ -24,7 +45,7 @@ type RegistrationKey struct {
 
 // Registration 注册信息
 type Registration struct {
-	ID                 TypeID // 缓存 TypeID 以提高性能
+	ID                 TypeID // 注册时分配，用于实例缓存下标
 	ServiceType        reflect.Type
 	ImplementationType reflect.Type
 	Lifetime           ServiceLifetime
@@ -32,29 +53,93 @@ type Registration struct {
 	Factory            interface{}
 	FactoryValue       reflect.Value
 	InputTypes         []reflect.Type
-	Interfaces         []reflect.Type
+	Dependencies       []Dependency         // 展开参数对象后的依赖列表
+	Interfaces         []reflect.Type       // 额外暴露的服务接口，与 ServiceType 共享同一实例
+	Inner              *Registration        // 装饰器包装的内层注册（仅装饰器）
+	Condition          func() (bool, error) // 条件注册：编译时求值，不成立时移除该注册
+}
+
+// Key 返回注册的主键（具体服务类型 + 服务键）
+func (r *Registration) Key() RegistrationKey {
+	return RegistrationKey{Type: r.ServiceType, Name: r.ServiceKey}
+}
+
+// binding 注册键到注册的绑定
+// 同一注册以具体类型和每个接口别名分别绑定一次
+type binding struct {
+	key RegistrationKey
+	reg *Registration
+}
+
+// singletonSlot 单例缓存槽，支持无锁读取与按需（惰性）创建
+type singletonSlot struct {
+	value atomic.Pointer[interface{}]
+	mu    sync.Mutex
+}
+
+// CompileOptions 编译选项
+type CompileOptions struct {
+	// ValidateAll 在实例化之前完整校验依赖图（缺失依赖、循环依赖、生命周期违规），
+	// 一次性返回所有问题，而不是在第一个错误处停止
+	ValidateAll bool
+
+	// LazySingletons 编译时不实例化单例，在首次解析时创建
+	LazySingletons bool
+
+	// MaxParallelism 提前实例化单例时的最大并发数，0 或 1 表示串行
+	MaxParallelism int
 }
 
 // Engine 容器引擎（不导出）
 type Engine struct {
-	registry      *TypeRegistry
 	graph         *DependencyGraph
-	registrations map[RegistrationKey]*Registration
-	singletons    atomic.Value // []interface{}
+	registrations map[RegistrationKey]*Registration // 每个键当前生效的注册（最后注册者优先）
+	bindings      []*binding                        // 按注册顺序保存的所有绑定，用于 ResolveAll
+	all           []*Registration                   // 按注册顺序保存所有注册（包括装饰器），索引即 TypeID
+	decorators    map[RegistrationKey][]*Registration
+	decorated     []RegistrationKey // 按首次装饰的顺序保存被装饰的键，编译时按此顺序链接装饰器
+	singletons    []singletonSlot   // 以 TypeID 为下标，编译时分配
+	created       []TypeID          // 单例的创建完成顺序（依赖先于依赖者），用于逆序释放
+	createdMu     sync.Mutex
+	stats         []factoryStats // 以 TypeID 为下标，记录工厂函数调用耗时
+	compileTime   atomic.Int64   // 编译耗时（纳秒）
+	parent        *Engine        // 父容器，未注册的服务回退到父容器解析（子容器）
 	compiled      atomic.Bool
 	mu            sync.RWMutex
 }
 
 func NewEngine() *Engine {
 	return &Engine{
-		registry:      NewTypeRegistry(),
-		graph:         NewDependencyGraph(),
 		registrations: make(map[RegistrationKey]*Registration),
+		decorators:    make(map[RegistrationKey][]*Registration),
 	}
 }
 
+// NewChildEngine 创建子容器引擎
+// 子容器的注册覆盖父容器的同名注册，其余服务（包括 []T 分组）回退到父容器解析
+func NewChildEngine(parent *Engine) *Engine {
+	e := NewEngine()
+	e.parent = parent
+	return e
+}
+
+// lookup 返回键当前生效的注册，本容器未注册时沿父容器链查找
+func (e *Engine) lookup(key RegistrationKey) (*Registration, bool) {
+	for engine := e; engine != nil; engine = engine.parent {
+		if reg, exists := engine.registrations[key]; exists {
+			return reg, true
+		}
+	}
+	return nil, false
+}
+
 // Register 注册服务
 func (e *Engine) Register(reg *Registration) error {
+	return e.RegisterKeyed(reg, reg.ServiceKey)
+}
+
+// RegisterKeyed 注册命名服务
+func (e *Engine) RegisterKeyed(reg *Registration, serviceKey string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -62,51 +147,116 @@ func (e *Engine) Register(reg *Registration) error {
 		return errors.New("cannot register after compilation")
 	}
 
-	// 验证工厂函数
-	factoryType := reflect.TypeOf(reg.Factory)
-	if factoryType.Kind() != reflect.Func {
-		return errors.New("factory must be a function")
+	if err := prepareRegistration(reg); err != nil {
+		return err
 	}
 
-	// 提取输入类型（依赖）
-	numIn := factoryType.NumIn()
-	reg.InputTypes = make([]reflect.Type, numIn)
-	for i := 0; i < numIn; i++ {
-		reg.InputTypes[i] = factoryType.In(i)
+	// 验证接口绑定
+	for _, iface := range reg.Interfaces {
+		if iface.Kind() != reflect.Interface {
+			return fmt.Errorf("%s is not an interface type", formatType(iface))
+		}
+		if !reg.ServiceType.Implements(iface) {
+			return fmt.Errorf("%s does not implement %s", formatType(reg.ServiceType), formatType(iface))
+		}
 	}
 
-	// 提取输出类型
-	if factoryType.NumOut() == 0 || factoryType.NumOut() > 2 {
-		return errors.New("factory must return 1 or 2 values")
+	// 使用服务键注册到 map
+	// 同一键可以注册多次：Resolve 返回最后一个，ResolveAll 按注册顺序返回全部
+	reg.ServiceKey = serviceKey
+	e.track(reg)
+	e.bind(reg.Key(), reg)
+
+	// 接口别名指向同一注册
+	for _, iface := range reg.Interfaces {
+		e.bind(RegistrationKey{Type: iface, Name: serviceKey}, reg)
 	}
-	reg.ServiceType = factoryType.Out(0)
 
-	// 缓存 reflect.Value
-	reg.FactoryValue = reflect.ValueOf(reg.Factory)
+	return nil
+}
 
-	// 注册到 map
-	// 使用提供的 Name（用于键控服务 Keyed Services）
-	key := RegistrationKey{Type: reg.ServiceType, Name: reg.ServiceKey}
-	e.registrations[key] = reg
+// Remove 移除键的所有绑定，返回被移除的注册（按注册顺序）
+// 移除具体类型的注册时，其接口别名一并移除；移除接口别名不影响具体类型
+func (e *Engine) Remove(key RegistrationKey) ([]*Registration, error) {
+	return e.removeWhere(func(k RegistrationKey) bool { return k == key })
+}
 
-	// 添加到依赖图
-	e.graph.AddNode(key, reg.InputTypes)
+// RemoveType 移除类型的所有绑定（包括命名服务）及其装饰器
+func (e *Engine) RemoveType(serviceType reflect.Type) ([]*Registration, error) {
+	removed, err := e.removeWhere(func(k RegistrationKey) bool { return k.Type == serviceType })
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key := range e.decorators {
+		if key.Type == serviceType {
+			delete(e.decorators, key)
+		}
+	}
+	return removed, nil
 }
 
-// RegisterKeyed 注册命名服务
-func (e *Engine) RegisterKeyed(reg *Registration, serviceKey string) error {
+func (e *Engine) removeWhere(match func(RegistrationKey) bool) ([]*Registration, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	if e.compiled.Load() {
-		return errors.New("cannot register after compilation")
+		return nil, errors.New("cannot remove after compilation")
 	}
 
+	var removed []*Registration
+	dropped := make(map[*Registration]bool)
+	for _, b := range e.bindings {
+		if match(b.key) {
+			removed = append(removed, b.reg)
+			// 只有移除主键时才连带移除该注册的接口别名
+			if b.reg.Key() == b.key {
+				dropped[b.reg] = true
+			}
+		}
+	}
+
+	e.keepBindings(func(b *binding) bool {
+		return !match(b.key) && !dropped[b.reg]
+	})
+
+	return removed, nil
+}
+
+// keepBindings 只保留满足 keep 的绑定，并重建当前生效的注册（调用方已持有锁）
+// 已分配的 ID 保持不变
+func (e *Engine) keepBindings(keep func(*binding) bool) {
+	bindings := e.bindings[:0]
+	e.registrations = make(map[RegistrationKey]*Registration)
+	for _, b := range e.bindings {
+		if !keep(b) {
+			continue
+		}
+		bindings = append(bindings, b)
+		e.registrations[b.key] = b.reg
+	}
+	e.bindings = bindings
+}
+
+// bind 将注册绑定到键，并设为该键当前生效的注册（调用方已持有锁）
+func (e *Engine) bind(key RegistrationKey, reg *Registration) {
+	e.registrations[key] = reg
+	e.bindings = append(e.bindings, &binding{key: key, reg: reg})
+}
+
+// track 记录注册并分配 ID（调用方已持有锁）
+func (e *Engine) track(reg *Registration) {
+	reg.ID = TypeID(len(e.all))
+	e.all = append(e.all, reg)
+}
+
+// prepareRegistration 验证工厂函数并提取服务类型与依赖
+func prepareRegistration(reg *Registration) error {
 	// 验证工厂函数
 	factoryType := reflect.TypeOf(reg.Factory)
-	if factoryType.Kind() != reflect.Func {
+	if factoryType == nil || factoryType.Kind() != reflect.Func {
 		return errors.New("factory must be a function")
 	}
 
@@ -117,6 +267,13 @@ func (e *Engine) RegisterKeyed(reg *Registration, serviceKey string) error {
 		reg.InputTypes[i] = factoryType.In(i)
 	}
 
+	// 展开参数对象，得到带键的依赖列表
+	deps, err := analyzeDependencies(reg.InputTypes)
+	if err != nil {
+		return err
+	}
+	reg.Dependencies = deps
+
 	// 提取输出类型
 	if factoryType.NumOut() == 0 || factoryType.NumOut() > 2 {
 		return errors.New("factory must return 1 or 2 values")
@@ -126,78 +283,284 @@ func (e *Engine) RegisterKeyed(reg *Registration, serviceKey string) error {
 	// 缓存 reflect.Value
 	reg.FactoryValue = reflect.ValueOf(reg.Factory)
 
-	// 使用服务键注册到 map
-	key := RegistrationKey{Type: reg.ServiceType, Name: serviceKey}
-	e.registrations[key] = reg
-
-	// 添加到依赖图
-	e.graph.AddNode(key, reg.InputTypes)
-
 	return nil
 }
 
-// Compile 编译容器
+// Compile 编译容器（使用默认选项：遇到第一个错误即停止，提前实例化所有单例）
 func (e *Engine) Compile() error {
+	return e.CompileWithOptions(CompileOptions{})
+}
+
+// CompileWithOptions 按选项编译容器
+func (e *Engine) CompileWithOptions(opts CompileOptions) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	if e.compiled.Load() {
 		return nil
 	}
+	start := time.Now()
 
-	// 拓扑排序和循环检测
-	sorted, err := e.graph.TopologicalSort()
-	if err != nil {
+	// 移除条件不成立的注册（条件在编译时求值，此时配置已经是最终结果）
+	if err := e.applyConditions(); err != nil {
 		return err
 	}
 
-	// 提前实例化所有 Singleton
-	singletons := make([]interface{}, len(e.registrations))
-	e.singletons.Store(singletons)
+	// 将装饰器链接到被装饰的注册上
+	decoratorErrs := e.applyDecorators()
+	e.bindRegisteredFuncs()
+
+	// 按最终的注册构建依赖图
+	e.buildGraph()
+
+	var sorted []RegistrationKey
+	if opts.ValidateAll {
+		// 收集所有问题后一次性报告
+		if err := e.validateAll(decoratorErrs); err != nil {
+			return err
+		}
+		sorted, _ = e.graph.TopologicalSort()
+	} else {
+		if len(decoratorErrs) > 0 {
+			return decoratorErrs[0]
+		}
+
+		// 拓扑排序和循环检测
+		var err error
+		sorted, err = e.graph.TopologicalSort()
+		if err != nil {
+			return err
+		}
+
+		// 生命周期校验：Singleton 不能捕获 Scoped/Transient 服务
+		if errs := e.lifetimeViolations(); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+
+	e.singletons = make([]singletonSlot, len(e.all))
+	e.stats = make([]factoryStats, len(e.all))
+
+	// 按依赖顺序提前实例化所有 Singleton
+	if !opts.LazySingletons {
+		regs := e.eagerSingletons(sorted)
+		var err error
+		if opts.MaxParallelism > 1 {
+			err = e.createSingletonsParallel(regs, opts.MaxParallelism)
+		} else {
+			err = e.createSingletons(regs)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	e.compileTime.Store(int64(time.Since(start)))
+	e.compiled.Store(true)
+	return nil
+}
+
+// eagerSingletons 按拓扑顺序返回需要提前实例化的 Singleton 注册（同一键的多个注册按注册顺序）
+// 接口别名与具体类型共享注册，因此只按主键收集
+func (e *Engine) eagerSingletons(sorted []RegistrationKey) []*Registration {
+	byKey := e.bindingsByKey()
+	var regs []*Registration
 	for _, key := range sorted {
-		reg := e.registrations[key]
-		if reg != nil {
-			// 缓存 TypeID 以提高性能
-			reg.ID = e.registry.GetID(key.Type, key.Name)
-
-			if reg.Lifetime == Singleton {
-				instance, err := e.createInstance(reg, []string{})
-				if err != nil {
-					return fmt.Errorf("failed to create singleton %v: %w", reg.ServiceType, err)
-				}
-				singletons[int(reg.ID)] = instance
+		for _, b := range byKey[key] {
+			if b.reg.Key() == key && b.reg.Lifetime == Singleton {
+				regs = append(regs, b.reg)
 			}
 		}
 	}
+	return regs
+}
 
-	e.compiled.Store(true)
+// createSingletons 按顺序串行创建单例，遇到第一个错误即停止
+func (e *Engine) createSingletons(regs []*Registration) error {
+	for _, reg := range regs {
+		if _, err := e.resolveSingleton(reg, []string{}); err != nil {
+			return fmt.Errorf("failed to create singleton %v: %w", reg.ServiceType, err)
+		}
+	}
 	return nil
 }
 
-// Resolve 解析服务（只支持 Singleton）
-func (e *Engine) Resolve(serviceType reflect.Type, name string) (interface{}, error) {
-	return e.resolveInternal(serviceType, name, []string{})
+// buildGraph 根据当前绑定构建依赖图（调用方已持有锁）
+// 接口别名节点依赖具体类型节点；装饰器的依赖归入被装饰的键
+func (e *Engine) buildGraph() {
+	e.graph = NewDependencyGraph()
+	for _, b := range e.bindings {
+		if b.reg.Key() != b.key {
+			e.graph.AddNode(b.key, []Dependency{{Key: b.reg.Key(), Field: -1}})
+			continue
+		}
+		for layer := b.reg; layer != nil; layer = layer.Inner {
+			e.graph.AddNode(b.key, layer.Dependencies)
+		}
+	}
+}
+
+// bindingsByKey 按键分组绑定，组内保持注册顺序
+func (e *Engine) bindingsByKey() map[RegistrationKey][]*binding {
+	result := make(map[RegistrationKey][]*binding)
+	for _, b := range e.bindings {
+		result[b.key] = append(result[b.key], b)
+	}
+	return result
 }
 
-func (e *Engine) resolveInternal(serviceType reflect.Type, name string, chain []string) (interface{}, error) {
+// lifetimeViolations 按注册顺序返回所有 Singleton 直接依赖 Scoped 或 Transient 服务（捕获依赖）的问题
+func (e *Engine) lifetimeViolations() []error {
+	var errs []error
+	for _, b := range e.bindings {
+		reg := b.reg
+		if reg.Key() != b.key || reg.Lifetime != Singleton {
+			continue
+		}
+		// 装饰器与被装饰的注册共享生命周期，逐层检查
+		for layer := reg; layer != nil; layer = layer.Inner {
+			for _, d := range layer.Dependencies {
+				for _, target := range e.dependencyTargets(d) {
+					// func() T 每次调用都重新解析，可以用来在 Singleton 中创建 Transient 实例
+					if target.reg.Lifetime == Singleton || (d.Func && target.reg.Lifetime == Transient) {
+						continue
+					}
+					tree := formatDependencyTree([]string{formatKey(b.key)}, fmt.Sprintf("%s (%s)", formatKey(target.key), target.reg.Lifetime))
+					errs = append(errs, fmt.Errorf("captive dependency detected:%s\n  Cause: singleton service cannot depend on %s service",
+						tree, strings.ToLower(target.reg.Lifetime.String())))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// dependencyTargets 返回依赖实际指向的绑定
+// 普通依赖指向该键当前生效的注册；[]T 依赖按注册顺序展开为 T 的所有绑定
+func (e *Engine) dependencyTargets(d Dependency) []*binding {
+	if !d.All {
+		if reg, exists := e.registrations[d.Key]; exists {
+			return []*binding{{key: d.Key, reg: reg}}
+		}
+		return nil
+	}
+
+	var targets []*binding
+	for _, b := range e.bindings {
+		if b.key.Type == d.Key.Type {
+			targets = append(targets, b)
+		}
+	}
+	return targets
+}
+
+// Resolve 从根容器解析服务（Scoped 服务必须通过 Scope 解析，Transient 每次创建新实例）
+func (e *Engine) Resolve(serviceType reflect.Type, name string) (interface{}, error) {
 	if !e.compiled.Load() {
 		return nil, errors.New("engine not compiled")
 	}
+	return e.resolveInternal(serviceType, name, nil, []string{})
+}
 
+func (e *Engine) resolveInternal(serviceType reflect.Type, name string, scope *Scope, chain []string) (interface{}, error) {
 	key := RegistrationKey{Type: serviceType, Name: name}
 
 	reg, exists := e.registrations[key]
+	if !exists && e.parent != nil {
+		// 子容器未注册的服务由父容器按父容器的注册解析
+		return e.parent.resolveInternal(serviceType, name, scope.parentScope(), chain)
+	}
 	if !exists {
 		// 只有在真正失败时才格式化依赖树
-		tree := formatDependencyTree(chain, formatType(serviceType))
+		tree := formatDependencyTree(chain, formatKey(key))
 		return nil, fmt.Errorf("service '%s' not found:%s\n  Cause: service not registered",
-			formatType(serviceType), tree)
+			formatKey(key), tree)
 	}
 
-	// 所有服务都是 Singleton - 从预编译缓存中检索
-	// 使用缓存的 ID 避免注册表锁
-	singletons := e.singletons.Load().([]interface{})
-	return singletons[int(reg.ID)], nil
+	return e.resolveRegistration(reg, scope, chain)
+}
+
+// resolveRegistration 按注册的生命周期返回实例
+func (e *Engine) resolveRegistration(reg *Registration, scope *Scope, chain []string) (interface{}, error) {
+	switch reg.Lifetime {
+	case Scoped:
+		if scope == nil {
+			key := reg.Key()
+			tree := formatDependencyTree(chain, formatKey(key))
+			return nil, fmt.Errorf("service '%s' cannot be resolved:%s\n  Cause: scoped service cannot be resolved from root provider",
+				formatKey(key), tree)
+		}
+		return scope.resolveScoped(reg, chain)
+	case Transient:
+		instance, err := e.createInstance(reg, scope, chain)
+		if err != nil {
+			return nil, err
+		}
+		// 作用域内创建的 Transient 由作用域负责释放；根容器中创建的不跟踪
+		if scope != nil {
+			scope.track(instance)
+		}
+		return instance, nil
+	default:
+		return e.resolveSingleton(reg, chain)
+	}
+}
+
+// resolveSingleton 从缓存返回 Singleton，不存在时创建（编译期间或惰性模式下首次解析）
+func (e *Engine) resolveSingleton(reg *Registration, chain []string) (interface{}, error) {
+	// 使用缓存的 ID 避免查找
+	slot := &e.singletons[reg.ID]
+	if instance := slot.value.Load(); instance != nil {
+		return *instance, nil
+	}
+
+	// 延迟依赖在构造函数中被调用时可能绕回正在创建的单例，加锁前先检测
+	if err := checkCycle(reg, chain); err != nil {
+		return nil, err
+	}
+
+	// 每个单例独立加锁：依赖链上的其他单例可以并发创建，循环依赖已在编译时排除
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if instance := slot.value.Load(); instance != nil {
+		return *instance, nil
+	}
+
+	instance, err := e.createInstance(reg, nil, chain)
+	if err != nil {
+		return nil, err
+	}
+	slot.value.Store(&instance)
+
+	e.createdMu.Lock()
+	e.created = append(e.created, reg.ID)
+	e.createdMu.Unlock()
+	return instance, nil
+}
+
+// checkCycle 检查注册是否已在依赖链中（只有构造函数中调用延迟依赖时才可能出现）
+func checkCycle(reg *Registration, chain []string) error {
+	if len(chain) == 0 {
+		return nil
+	}
+	current := formatKey(RegistrationKey{Type: reg.ServiceType, Name: reg.ServiceKey})
+	if inChain(chain, current) {
+		return circularError(chain, current)
+	}
+	return nil
+}
+
+func inChain(chain []string, name string) bool {
+	for _, c := range chain {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func circularError(chain []string, current string) error {
+	return fmt.Errorf("circular dependency detected:%s", formatDependencyTree(chain, current+" (Circular)"))
 }
 
 // formatDependencyTree 将依赖链格式化为树状结构
@@ -219,6 +582,14 @@ func formatDependencyTree(chain []string, current string) string {
 	return builder
 }
 
+// formatKey 返回注册键的显示名称，命名服务附带键名
+func formatKey(key RegistrationKey) string {
+	if key.Name == "" {
+		return formatType(key.Type)
+	}
+	return fmt.Sprintf("%s(%s)", formatType(key.Type), key.Name)
+}
+
 // formatType 返回完全限定的类型名称
 func formatType(t reflect.Type) string {
 	if t == nil {
@@ -236,70 +607,113 @@ func formatType(t reflect.Type) string {
 	return t.PkgPath() + "." + t.Name()
 }
 
-// ResolveAll 解析特定类型的所有服务
+// ResolveAll 从根容器按注册顺序解析特定类型的所有服务
 func (e *Engine) ResolveAll(serviceType reflect.Type) ([]interface{}, error) {
 	if !e.compiled.Load() {
 		return nil, errors.New("engine not compiled")
 	}
+	return e.resolveAll(serviceType, nil)
+}
 
-	var results []interface{}
+func (e *Engine) resolveAll(serviceType reflect.Type, scope *Scope) ([]interface{}, error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	for key := range e.registrations {
-		if key.Type == serviceType {
-			instance, err := e.Resolve(serviceType, key.Name)
-			if err != nil {
-				return nil, err
-			}
-			results = append(results, instance)
-		}
+	return e.resolveGroup(serviceType, scope, []string{})
+}
+
+// resolveGroup 按注册顺序解析特定类型的所有注册（不加锁，调用方负责）
+// 子容器没有该类型的注册时回退到父容器
+func (e *Engine) resolveGroup(serviceType reflect.Type, scope *Scope, chain []string) ([]interface{}, error) {
+	targets := e.dependencyTargets(Dependency{Key: RegistrationKey{Type: serviceType}, All: true})
+	if len(targets) == 0 && e.parent != nil {
+		return e.parent.resolveGroup(serviceType, scope.parentScope(), chain)
 	}
 
+	var results []interface{}
+	for _, target := range targets {
+		instance, err := e.resolveRegistration(target.reg, scope, chain)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, instance)
+	}
 	return results, nil
 }
 
 // createInstance 创建实例
-func (e *Engine) createInstance(reg *Registration, chain []string) (interface{}, error) {
+// scope 为 nil 时表示在根容器中创建（Singleton）
+func (e *Engine) createInstance(reg *Registration, scope *Scope, chain []string) (interface{}, error) {
 	// 将当前服务添加到链中
-	currentType := formatType(reg.ServiceType)
+	currentType := formatKey(RegistrationKey{Type: reg.ServiceType, Name: reg.ServiceKey})
+	if inChain(chain, currentType) {
+		return nil, circularError(chain, currentType)
+	}
 	newChain := append(chain, currentType)
 
-	// 解析依赖
+	// 参数对象先创建零值，再逐字段填充
 	args := make([]reflect.Value, len(reg.InputTypes))
-	for i, depType := range reg.InputTypes {
-		// 在编译期间，直接使用 resolveDuringCompile
-		var dep interface{}
-		var err error
+	for i, inputType := range reg.InputTypes {
+		if IsParamObject(inputType) {
+			args[i] = reflect.New(inputType).Elem()
+		}
+	}
 
-		if !e.compiled.Load() {
-			// 编译期间的解析
-			dep, err = e.resolveDuringCompile(depType, "", newChain)
-		} else {
-			// 运行时解析
-			// 注意：在运行时，ResolveInternal 仅返回单例。
-			// 理想情况下，如果单例已预先创建，我们不应在运行时调用 createInstance。
-			// 但如果将来支持 transient/scoped，则需要此路径。
-			// 当前实现仅支持 Singleton 并预先创建它们。
-			// 但是，Resolve 调用 resolveInternal，后者进行查找。
-			dep, err = e.resolveInternal(depType, "", newChain)
+	// 装饰器的第一个参数是内层实例（被装饰的原始服务或上一层装饰器）
+	if reg.Inner != nil {
+		inner, err := e.resolveRegistration(reg.Inner, scope, chain)
+		if err != nil {
+			return nil, err
 		}
+		args[0] = reflect.ValueOf(inner)
+	}
 
+	// 解析依赖
+	var deferred *deferredState
+	for _, d := range reg.Dependencies {
+		// 从递归调用返回的错误已经具有从 ROOT 开始的完整依赖树，
+		// 因为我们向下传递了 newChain，所以这里直接返回即可。
+		if d.All {
+			instances, err := e.resolveGroup(d.Key.Type, scope, newChain)
+			if err != nil {
+				return nil, err
+			}
+			slice := reflect.MakeSlice(reflect.SliceOf(d.Key.Type), len(instances), len(instances))
+			for i, instance := range instances {
+				slice.Index(i).Set(reflect.ValueOf(instance))
+			}
+			args[d.Index].Field(d.Field).Set(slice)
+			continue
+		}
+
+		// 可选依赖未注册时保持零值
+		if d.Optional {
+			if _, exists := e.lookup(d.Key); !exists {
+				continue
+			}
+		}
+
+		// 延迟依赖只绑定解析函数，实际解析推迟到首次使用
+		if d.Deferred() {
+			if deferred == nil {
+				deferred = newDeferredState(newChain)
+			}
+			setValue(args, d, e.deferredValue(paramType(reg.InputTypes, d), d, scope, deferred))
+			continue
+		}
+
+		dep, err := e.resolveInternal(d.Key.Type, d.Key.Name, scope, newChain)
 		if err != nil {
-			// 如果已经是我们格式化的错误，只需包装或返回它
-			// 但我们希望确保链被保留，如果错误来自深层
-			// 实际上，深层的错误已经包含了树。
-			// 我们可能只想在需要时添加我们的信息，但通常深层错误就足够了。
-			// 但是，为了匹配树结构，深层错误具有从它的视角看的完整树。
-			// 如果我们想显示从 ROOT 开始的完整树，我们依赖于向下传递的 chain。
-			// 从递归调用返回的错误已经具有完整的树，因为我们传递了 'newChain'。
 			return nil, err
 		}
-		args[i] = reflect.ValueOf(dep)
+		setDependency(args, d, dep)
 	}
 
 	// 调用工厂函数
-	results := reg.FactoryValue.Call(args)
+	if deferred != nil {
+		defer deferred.constructing.Store(false)
+	}
+	results := e.callFactory(reg, args)
 
 	// 检查错误
 	if len(results) == 2 && !results[1].IsNil() {
@@ -309,37 +723,22 @@ func (e *Engine) createInstance(reg *Registration, chain []string) (interface{},
 	return results[0].Interface(), nil
 }
 
-// resolveDuringCompile 在编译期间解析依赖（不检查 compiled 标志）
-func (e *Engine) resolveDuringCompile(serviceType reflect.Type, name string, chain []string) (interface{}, error) {
-	key := RegistrationKey{Type: serviceType, Name: name}
-
-	reg, exists := e.registrations[key]
-	if !exists {
-		tree := formatDependencyTree(chain, formatType(serviceType))
-		return nil, fmt.Errorf("service '%s' not found:%s\n  Cause: service not registered",
-			formatType(serviceType), tree)
+// setDependency 将解析结果写入参数或参数对象字段
+func setDependency(args []reflect.Value, d Dependency, value interface{}) {
+	v := reflect.ValueOf(value)
+	if value == nil {
+		v = reflect.Zero(d.Key.Type)
 	}
+	setValue(args, d, v)
+}
 
-	// 如果是 Singleton，检查是否已经创建
-	if reg.Lifetime == Singleton {
-		singletons := e.singletons.Load()
-		if singletons != nil {
-			// 如果可用，使用缓存的 ID，否则回退（尽管 Compile 会设置它）
-			id := reg.ID
-			if id == 0 { // 以防万一尚未设置（循环依赖检查处理顺序）
-				id = e.registry.GetID(key.Type, key.Name)
-			}
-
-			if id >= 0 && int(id) < len(singletons.([]interface{})) {
-				if instance := singletons.([]interface{})[int(id)]; instance != nil {
-					return instance, nil
-				}
-			}
-		}
+// setValue 将值写入参数或参数对象字段
+func setValue(args []reflect.Value, d Dependency, v reflect.Value) {
+	if d.Field < 0 {
+		args[d.Index] = v
+		return
 	}
-
-	// 递归创建实例
-	return e.createInstance(reg, chain)
+	args[d.Index].Field(d.Field).Set(v)
 }
 
 // Contains 检查服务是否存在
@@ -348,7 +747,7 @@ func (e *Engine) Contains(serviceType reflect.Type, name string) bool {
 	defer e.mu.RUnlock()
 
 	key := RegistrationKey{Type: serviceType, Name: name}
-	_, exists := e.registrations[key]
+	_, exists := e.lookup(key)
 	return exists
 }
 
@@ -361,38 +760,40 @@ func (e *Engine) GetRegistration(key RegistrationKey) (*Registration, bool) {
 	return reg, exists
 }
 
-// GetSingletons 返回所有 Singleton 实例（用于资源清理）
+// GetSingletons 按创建完成顺序返回所有已创建的 Singleton 实例（用于资源清理）
+// 依赖总是先于依赖它的服务完成创建，逆序释放即可保证服务释放时其依赖仍然可用
 func (e *Engine) GetSingletons() []interface{} {
 	if !e.compiled.Load() {
 		return nil
 	}
 
-	singletons := e.singletons.Load()
-	if singletons == nil {
-		return nil
-	}
-
-	singletonsSlice := singletons.([]interface{})
-	result := make([]interface{}, 0, len(singletonsSlice))
+	e.createdMu.Lock()
+	defer e.createdMu.Unlock()
 
-	for _, instance := range singletonsSlice {
-		if instance != nil {
-			result = append(result, instance)
-		}
+	result := make([]interface{}, 0, len(e.created))
+	for _, id := range e.created {
+		result = append(result, *e.singletons[id].value.Load())
 	}
 
 	return result
 }
 
-// GetAllRegistrations 返回所有注册信息
-func (e *Engine) GetAllRegistrations() map[RegistrationKey]*Registration {
+// GetAllRegistrations 按注册顺序返回所有服务注册（不含接口别名和装饰器）
+func (e *Engine) GetAllRegistrations() []*Registration {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	// Return a copy to avoid external modifications
-	result := make(map[RegistrationKey]*Registration, len(e.registrations))
-	for k, v := range e.registrations {
-		result[k] = v
+	result := make([]*Registration, 0, len(e.bindings))
+	for _, b := range e.bindings {
+		if b.reg.Key() != b.key {
+			continue
+		}
+		// 装饰后绑定指向最外层装饰器，返回原始注册
+		reg := b.reg
+		for reg.Inner != nil {
+			reg = reg.Inner
+		}
+		result = append(result, reg)
 	}
 	return result
 }
@@ -9,6 +9,7 @@ import (
 // DependencyGraph 依赖图
 type DependencyGraph struct {
 	nodes map[RegistrationKey]*GraphNode
+	order []RegistrationKey // 节点添加顺序，保证排序结果确定
 	mu    sync.RWMutex
 }
 
@@ -16,6 +17,7 @@ type DependencyGraph struct {
 type GraphNode struct {
 	Key          RegistrationKey
 	Dependencies []RegistrationKey
+	Groups       []reflect.Type // 依赖该类型的所有注册（[]T 参数对象字段）
 	Visited      bool
 	InStack      bool
 }
@@ -26,40 +28,84 @@ func NewDependencyGraph() *DependencyGraph {
 	}
 }
 
-func (g *DependencyGraph) AddNode(key RegistrationKey, dependencies []reflect.Type) {
+// AddNode 添加节点，依赖记录注册键（包含命名服务的键名）
+// 同一键多次添加时合并依赖
+func (g *DependencyGraph) AddNode(key RegistrationKey, dependencies []Dependency) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	node := &GraphNode{
-		Key:          key,
-		Dependencies: make([]RegistrationKey, len(dependencies)),
+	node, exists := g.nodes[key]
+	if !exists {
+		node = &GraphNode{Key: key}
+		g.nodes[key] = node
+		g.order = append(g.order, key)
 	}
+	node.addDependencies(dependencies)
+}
 
-	for i, dep := range dependencies {
-		node.Dependencies[i] = RegistrationKey{Type: dep, Name: ""}
+func (n *GraphNode) addDependencies(dependencies []Dependency) {
+	for _, dep := range dependencies {
+		// 延迟依赖在构造之后才解析，不构成构造顺序上的边
+		if dep.Deferred() {
+			continue
+		}
+		if dep.All {
+			n.Groups = append(n.Groups, dep.Key.Type)
+		} else {
+			n.Dependencies = append(n.Dependencies, dep.Key)
+		}
 	}
-
-	g.nodes[key] = node
 }
 
-// Duplicate formatDependencyTree here or move it to a shared utils file.
-// Since they are in the same package 'internal', we can use the one from engine.go if it's exported or in the same package.
-// It is in the same package 'internal', so we can use it directly if it's not private to engine.go
-// Wait, formatDependencyTree was defined in engine.go which is package internal.
-// So we can use it here.
+// depNodes 返回节点的所有依赖节点（展开 Groups）
+func (g *DependencyGraph) depNodes(node *GraphNode) []*GraphNode {
+	var result []*GraphNode
+	for _, depKey := range node.Dependencies {
+		if depNode, exists := g.nodes[depKey]; exists {
+			result = append(result, depNode)
+		}
+	}
+	for _, groupType := range node.Groups {
+		for _, key := range g.order {
+			if key.Type == groupType {
+				result = append(result, g.nodes[key])
+			}
+		}
+	}
+	return result
+}
 
+// TopologicalSort 拓扑排序，遇到第一个循环依赖时返回错误
 func (g *DependencyGraph) TopologicalSort() ([]RegistrationKey, error) {
+	sorted, errs := g.sort(false)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return sorted, nil
+}
+
+// Cycles 返回依赖图中的所有循环依赖（每个循环报告一次）
+func (g *DependencyGraph) Cycles() []error {
+	_, errs := g.sort(true)
+	return errs
+}
+
+// sort 深度优先遍历；collectAll 为 true 时跳过回边继续遍历，收集所有循环
+func (g *DependencyGraph) sort(collectAll bool) ([]RegistrationKey, []error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	for _, node := range g.nodes {
+		node.Visited = false
+		node.InStack = false
+	}
+
 	var sorted []RegistrationKey
+	var cycles []error
 
 	var visit func(*GraphNode, []string) error
 	visit = func(node *GraphNode, path []string) error {
-		currentName := formatType(node.Key.Type)
-		if node.Key.Name != "" {
-			currentName += fmt.Sprintf("(%s)", node.Key.Name)
-		}
+		currentName := formatKey(node.Key)
 
 		if node.InStack {
 			// Found a cycle
@@ -74,7 +120,11 @@ func (g *DependencyGraph) TopologicalSort() ([]RegistrationKey, error) {
 			// Note: 'path' contains [A, B], and currentName is A.
 			// However, formatDependencyTree expects the chain leading TO the error.
 			tree := formatDependencyTree(path, currentName+" (Circular)")
-			return fmt.Errorf("circular dependency detected:%s", tree)
+			cycles = append(cycles, fmt.Errorf("circular dependency detected:%s", tree))
+			if collectAll {
+				return nil
+			}
+			return cycles[len(cycles)-1]
 		}
 		if node.Visited {
 			return nil
@@ -85,11 +135,9 @@ func (g *DependencyGraph) TopologicalSort() ([]RegistrationKey, error) {
 
 		newPath := append(path, currentName)
 
-		for _, depKey := range node.Dependencies {
-			if depNode, exists := g.nodes[depKey]; exists {
-				if err := visit(depNode, newPath); err != nil {
-					return err
-				}
+		for _, depNode := range g.depNodes(node) {
+			if err := visit(depNode, newPath); err != nil {
+				return err
 			}
 		}
 
@@ -98,13 +146,13 @@ func (g *DependencyGraph) TopologicalSort() ([]RegistrationKey, error) {
 		return nil
 	}
 
-	for _, node := range g.nodes {
-		if !node.Visited {
+	for _, key := range g.order {
+		if node := g.nodes[key]; !node.Visited {
 			if err := visit(node, []string{}); err != nil {
-				return nil, err
+				return nil, cycles
 			}
 		}
 	}
 
-	return sorted, nil
+	return sorted, cycles
 }
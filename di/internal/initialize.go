@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// initializable 与 di.IInitializable 结构相同，避免 internal 依赖 di 包
+type initializable interface {
+	Initialize(ctx context.Context) error
+}
+
+// InitializeSingletons 按创建完成顺序（依赖先于依赖者）初始化已创建的单例
+// 遇到第一个失败或 ctx 到期时停止，错误以依赖树形式给出失败服务的位置
+// 同一实例（例如多个键注册的同一 AddInstance 对象）只初始化一次
+func (e *Engine) InitializeSingletons(ctx context.Context) error {
+	if !e.compiled.Load() {
+		return nil
+	}
+
+	e.createdMu.Lock()
+	created := append([]TypeID(nil), e.created...)
+	e.createdMu.Unlock()
+
+	seen := make(map[interface{}]bool)
+	for _, id := range created {
+		instance := *e.singletons[id].value.Load()
+		init, ok := instance.(initializable)
+		if !ok {
+			continue
+		}
+		if reflect.TypeOf(instance).Comparable() {
+			if seen[instance] {
+				continue
+			}
+			seen[instance] = true
+		}
+
+		if err := InitializeInstance(ctx, init); err != nil {
+			key := e.all[id].Key()
+			tree := formatDependencyTree(e.dependentChain(key), formatKey(key))
+			return fmt.Errorf("failed to initialize service '%s':%s\n  Cause: %w", formatKey(key), tree, err)
+		}
+	}
+	return nil
+}
+
+// InitializeInstance 调用 Initialize，ctx 到期时不再等待并返回 ctx.Err()
+func InitializeInstance(ctx context.Context, init initializable) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		return init.Initialize(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- init.Initialize(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dependentChain 返回从某个根服务（没有其他服务依赖它）到 key 的依赖链（不含 key）
+// 用于在错误中说明失败的服务是被谁引入的；key 本身是根服务时返回空链
+func (e *Engine) dependentChain(key RegistrationKey) []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	g := e.graph
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	hasDependents := make(map[RegistrationKey]bool)
+	for _, k := range g.order {
+		for _, dep := range g.depNodes(g.nodes[k]) {
+			hasDependents[dep.Key] = true
+		}
+	}
+
+	var path []string
+	visited := make(map[RegistrationKey]bool)
+	var find func(k RegistrationKey) bool
+	find = func(k RegistrationKey) bool {
+		if k == key {
+			return true
+		}
+		if visited[k] {
+			return false
+		}
+		visited[k] = true
+		path = append(path, formatKey(k))
+		for _, dep := range g.depNodes(g.nodes[k]) {
+			if find(dep.Key) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	for _, k := range g.order {
+		if !hasDependents[k] && k != key && find(k) {
+			return path
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Lazy 延迟解析的依赖，首次调用 Value 时才从容器解析，之后返回缓存的结果
+type Lazy[T any] struct {
+	cell *lazyCell
+}
+
+// lazyCell Lazy 的共享状态，Lazy 按值传递时仍只解析一次
+type lazyCell struct {
+	once    sync.Once
+	resolve func() (interface{}, error)
+	value   interface{}
+	err     error
+}
+
+// Value 返回依赖实例，首次调用时解析（解析失败时 panic）
+func (l Lazy[T]) Value() T {
+	if l.cell == nil {
+		panic(fmt.Sprintf("lazy %s is not initialized: it must be injected by the container", formatType(l.lazyTarget())))
+	}
+	l.cell.once.Do(func() {
+		l.cell.value, l.cell.err = l.cell.resolve()
+		l.cell.resolve = nil
+	})
+	if l.cell.err != nil {
+		panic(l.cell.err)
+	}
+
+	var zero T
+	if l.cell.value == nil {
+		return zero
+	}
+	return l.cell.value.(T)
+}
+
+func (Lazy[T]) lazyTarget() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (l *Lazy[T]) bindLazy(resolve func() (interface{}, error)) {
+	l.cell = &lazyCell{resolve: resolve}
+}
+
+// lazyBinder 由 *Lazy[T] 实现，用于在反射中识别并绑定 Lazy 依赖
+type lazyBinder interface {
+	lazyTarget() reflect.Type
+	bindLazy(resolve func() (interface{}, error))
+}
+
+var lazyBinderType = reflect.TypeOf((*lazyBinder)(nil)).Elem()
+
+// deferredTarget 判断参数类型是否为延迟依赖，返回实际依赖的服务类型
+// Lazy[T] 返回 lazy = true；未命名的 func() T 返回 fn = true
+func deferredTarget(t reflect.Type) (target reflect.Type, lazy bool, fn bool) {
+	if t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(lazyBinderType) {
+		return reflect.New(t).Interface().(lazyBinder).lazyTarget(), true, false
+	}
+	if t.Kind() == reflect.Func && t.Name() == "" && t.NumIn() == 0 && t.NumOut() == 1 {
+		return t.Out(0), false, true
+	}
+	return t, false, false
+}
+
+// deferredState 同一次构造中创建的延迟依赖共享的状态
+// 构造函数执行期间（包括编译时创建单例）调用延迟依赖时沿用当前依赖链，绕回正在创建的服务时返回循环依赖错误而不是死锁；
+// 构造完成后从空的依赖链开始解析
+type deferredState struct {
+	chain        []string
+	constructing atomic.Bool
+}
+
+func newDeferredState(chain []string) *deferredState {
+	state := &deferredState{chain: slices.Clip(slices.Clone(chain))}
+	state.constructing.Store(true)
+	return state
+}
+
+// deferredValue 为延迟依赖创建参数值，实际解析推迟到 Value() 或函数调用时
+// 作用域内创建的延迟依赖在该作用域中解析，否则从根容器解析
+// 延迟依赖只会在编译确定最终注册之后创建，因此根容器解析不要求编译已经完成
+func (e *Engine) deferredValue(paramType reflect.Type, d Dependency, scope *Scope, state *deferredState) reflect.Value {
+	resolve := func() (interface{}, error) {
+		chain := []string{}
+		if state.constructing.Load() {
+			chain = state.chain
+		}
+
+		var instance interface{}
+		var err error
+		if scope != nil {
+			instance, err = scope.resolve(d.Key.Type, d.Key.Name, chain)
+		} else {
+			instance, err = e.resolveInternal(d.Key.Type, d.Key.Name, nil, chain)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve deferred dependency '%s': %w", formatKey(d.Key), err)
+		}
+		return instance, nil
+	}
+
+	if d.Lazy {
+		ptr := reflect.New(paramType)
+		ptr.Interface().(lazyBinder).bindLazy(resolve)
+		return ptr.Elem()
+	}
+
+	// func() T 每次调用都重新解析，Transient 服务每次返回新实例
+	return reflect.MakeFunc(paramType, func([]reflect.Value) []reflect.Value {
+		instance, err := resolve()
+		if err != nil {
+			panic(err)
+		}
+		// MakeFunc 要求返回值类型与声明完全一致（接口类型需要显式装箱）
+		result := reflect.New(d.Key.Type).Elem()
+		if instance != nil {
+			result.Set(reflect.ValueOf(instance))
+		}
+		return []reflect.Value{result}
+	})
+}
+
+// bindRegisteredFuncs 已注册为服务的 func() T 类型按普通依赖注入，而不是作为工厂（调用方已持有锁）
+func (e *Engine) bindRegisteredFuncs() {
+	for _, reg := range e.all {
+		for i, d := range reg.Dependencies {
+			if !d.Func {
+				continue
+			}
+			key := RegistrationKey{Type: paramType(reg.InputTypes, d), Name: d.Key.Name}
+			if _, exists := e.lookup(key); exists {
+				reg.Dependencies[i].Key = key
+				reg.Dependencies[i].Func = false
+			}
+		}
+	}
+}
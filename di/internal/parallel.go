@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// createSingletonsParallel 使用有界 worker 池并发创建单例（调用方已持有锁）
+//
+// regs 按拓扑顺序排列，依赖总在依赖者之前；一个单例在其所有单例依赖创建完成后才会被调度，
+// 因此互不依赖的分支可以并发创建。
+//
+// 错误与串行模式一致：返回 regs 中位置最靠前的失败。出现错误后，位置在其之前的单例继续创建，
+// 位置在其之后的不再调度——串行模式会先遇到的错误一定会被执行到。
+// 单例缓存仍以注册 ID 为下标，与创建顺序无关。
+func (e *Engine) createSingletonsParallel(regs []*Registration, workers int) error {
+	n := len(regs)
+	if n == 0 {
+		return nil
+	}
+
+	index := make(map[*Registration]int, n)
+	for i, reg := range regs {
+		index[reg] = i
+	}
+
+	// 计算每个单例尚未完成的依赖数，以及完成后需要通知的依赖者
+	pending := make([]int, n)
+	dependents := make([][]int, n)
+	for i, reg := range regs {
+		seen := make(map[int]bool)
+		for layer := reg; layer != nil; layer = layer.Inner {
+			for _, d := range layer.Dependencies {
+				if d.Deferred() {
+					continue
+				}
+				for _, target := range e.dependencyTargets(d) {
+					j, ok := index[target.reg]
+					if !ok || j == i || seen[j] {
+						continue
+					}
+					seen[j] = true
+					pending[i]++
+					dependents[j] = append(dependents[j], i)
+				}
+			}
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		errs     = make([]error, n)
+		firstErr = n // 已失败单例中最靠前的位置
+		ready    = make(chan int, n)
+	)
+
+	// schedule 将单例放入就绪队列（每个单例最多调度一次，队列不会阻塞）
+	schedule := func(i int) {
+		wg.Add(1)
+		ready <- i
+	}
+	for i := range regs {
+		if pending[i] == 0 {
+			schedule(i)
+		}
+	}
+
+	if workers > n {
+		workers = n
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range ready {
+				mu.Lock()
+				skip := i > firstErr
+				mu.Unlock()
+
+				var err error
+				if !skip {
+					_, err = e.resolveSingleton(regs[i], []string{})
+				}
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					errs[i] = err
+					if i < firstErr {
+						firstErr = i
+					}
+				case !skip:
+					for _, k := range dependents[i] {
+						pending[k]--
+						if pending[k] == 0 {
+							schedule(k)
+						}
+					}
+				}
+				mu.Unlock()
+				wg.Done()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(ready)
+
+	if firstErr < n {
+		return fmt.Errorf("failed to create singleton %v: %w", regs[firstErr].ServiceType, errs[firstErr])
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"reflect"
+	"runtime/metrics"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// factoryStats 单个注册的工厂函数调用统计，以 TypeID 为下标
+type factoryStats struct {
+	calls atomic.Int64
+	total atomic.Int64 // 累计耗时（纳秒）
+	max   atomic.Int64 // 单次最长耗时（纳秒）
+	alloc atomic.Int64 // Singleton 构造期间的堆分配字节数（近似值）
+}
+
+// ConstructorStat 工厂函数调用统计
+type ConstructorStat struct {
+	Name           string // 服务键的显示名称
+	Lifetime       ServiceLifetime
+	Calls          int64
+	Total          time.Duration
+	Max            time.Duration
+	AllocatedBytes uint64
+}
+
+// heapAllocsMetric 累计堆分配字节数，读取时不需要停止世界
+const heapAllocsMetric = "/gc/heap/allocs:bytes"
+
+// callFactory 调用工厂函数并记录耗时
+// 只统计工厂函数本身：依赖在调用前已解析完成，不计入
+// Singleton 只构造一次，额外记录其构造期间的堆分配；并发构造时会包含其他 goroutine 的分配
+func (e *Engine) callFactory(reg *Registration, args []reflect.Value) []reflect.Value {
+	stats := &e.stats[reg.ID]
+
+	var sample []metrics.Sample
+	if reg.Lifetime == Singleton {
+		sample = []metrics.Sample{{Name: heapAllocsMetric}}
+		metrics.Read(sample)
+	}
+	before := sampleUint64(sample)
+
+	start := time.Now()
+	results := reg.FactoryValue.Call(args)
+	elapsed := int64(time.Since(start))
+
+	if sample != nil {
+		metrics.Read(sample)
+		stats.alloc.Add(int64(sampleUint64(sample) - before))
+	}
+	stats.calls.Add(1)
+	stats.total.Add(elapsed)
+	for {
+		m := stats.max.Load()
+		if elapsed <= m || stats.max.CompareAndSwap(m, elapsed) {
+			break
+		}
+	}
+	return results
+}
+
+func sampleUint64(sample []metrics.Sample) uint64 {
+	if len(sample) == 0 || sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// ConstructorStats 返回所有被调用过的工厂函数的统计，按累计耗时从高到低排序
+func (e *Engine) ConstructorStats() []ConstructorStat {
+	if !e.compiled.Load() {
+		return nil
+	}
+
+	var result []ConstructorStat
+	for id := range e.stats {
+		stats := &e.stats[id]
+		calls := stats.calls.Load()
+		if calls == 0 {
+			continue
+		}
+		reg := e.all[id]
+		result = append(result, ConstructorStat{
+			Name:           formatKey(reg.Key()),
+			Lifetime:       reg.Lifetime,
+			Calls:          calls,
+			Total:          time.Duration(stats.total.Load()),
+			Max:            time.Duration(stats.max.Load()),
+			AllocatedBytes: uint64(stats.alloc.Load()),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Total > result[j].Total
+	})
+	return result
+}
+
+// CompileDuration 返回编译（包括提前实例化单例）的耗时
+func (e *Engine) CompileDuration() time.Duration {
+	return time.Duration(e.compileTime.Load())
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Scope 作用域，缓存 Scoped 服务实例
+// 同一作用域内的 Scoped 服务只创建一次；作用域内创建的 Scoped 和 Transient 实例
+// 在作用域结束时按创建顺序的逆序（LIFO）释放
+type Scope struct {
+	engine   *Engine
+	slots    map[TypeID]*scopeSlot
+	created  []interface{} // 按创建顺序记录，用于 LIFO 释放
+	parent   *Scope        // 父容器中的对应作用域（子容器），首次回退解析时创建
+	disposed bool
+	mu       sync.Mutex // 只保护上面的字段，不在创建实例期间持有
+}
+
+// scopeSlot 单个 Scoped 注册的实例缓存
+// 每个槽持有独立的锁，构造函数中可以继续解析同一作用域的其他服务（包括 Lazy 和 func() T）
+type scopeSlot struct {
+	value atomic.Pointer[interface{}]
+	mu    sync.Mutex
+}
+
+// NewScope 创建新的作用域
+func (e *Engine) NewScope() (*Scope, error) {
+	if !e.compiled.Load() {
+		return nil, errors.New("engine not compiled")
+	}
+	return newScope(e), nil
+}
+
+func newScope(e *Engine) *Scope {
+	return &Scope{
+		engine: e,
+		slots:  make(map[TypeID]*scopeSlot),
+	}
+}
+
+// Resolve 在作用域内解析服务
+func (s *Scope) Resolve(serviceType reflect.Type, name string) (interface{}, error) {
+	return s.resolve(serviceType, name, []string{})
+}
+
+// resolve 以给定的依赖链在作用域内解析服务
+func (s *Scope) resolve(serviceType reflect.Type, name string, chain []string) (interface{}, error) {
+	if err := s.checkDisposed(); err != nil {
+		return nil, err
+	}
+	return s.engine.resolveInternal(serviceType, name, s, chain)
+}
+
+// ResolveAll 在作用域内解析特定类型的所有服务
+func (s *Scope) ResolveAll(serviceType reflect.Type) ([]interface{}, error) {
+	if err := s.checkDisposed(); err != nil {
+		return nil, err
+	}
+	return s.engine.resolveAll(serviceType, s)
+}
+
+func (s *Scope) checkDisposed() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.disposed {
+		return errors.New("scope disposed")
+	}
+	return nil
+}
+
+// resolveScoped 返回作用域缓存中的实例，不存在时创建
+// 只锁定该注册的槽，同一作用域的不同服务可以在创建过程中递归或并发解析
+func (s *Scope) resolveScoped(reg *Registration, chain []string) (interface{}, error) {
+	s.mu.Lock()
+	if s.disposed {
+		s.mu.Unlock()
+		return nil, errors.New("scope disposed")
+	}
+	slot, ok := s.slots[reg.ID]
+	if !ok {
+		slot = &scopeSlot{}
+		s.slots[reg.ID] = slot
+	}
+	s.mu.Unlock()
+
+	if instance := slot.value.Load(); instance != nil {
+		return *instance, nil
+	}
+	// 同一调用链再次进入正在创建的槽会死锁，先检测循环
+	if err := checkCycle(reg, chain); err != nil {
+		return nil, err
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if instance := slot.value.Load(); instance != nil {
+		return *instance, nil
+	}
+
+	instance, err := s.engine.createInstance(reg, s, chain)
+	if err != nil {
+		return nil, err
+	}
+	slot.value.Store(&instance)
+	s.track(instance)
+	return instance, nil
+}
+
+// parentScope 返回父容器中的对应作用域，不存在时创建
+// 父容器的 Scoped 服务在子容器的作用域中解析时缓存在这里，并随本作用域一起释放
+func (s *Scope) parentScope() *Scope {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.parent == nil {
+		s.parent = newScope(s.engine.parent)
+	}
+	return s.parent
+}
+
+// track 记录作用域内创建的实例，以便作用域结束时释放
+func (s *Scope) track(instance interface{}) {
+	s.mu.Lock()
+	s.created = append(s.created, instance)
+	s.mu.Unlock()
+}
+
+// Close 结束作用域，返回按创建顺序逆序（LIFO）排列的实例，供调用方释放
+// 重复调用返回 nil
+func (s *Scope) Close() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.disposed {
+		return nil
+	}
+	s.disposed = true
+
+	result := make([]interface{}, len(s.created))
+	for i, instance := range s.created {
+		result[len(s.created)-1-i] = instance
+	}
+	s.slots = nil
+	s.created = nil
+
+	// 本作用域的实例可能依赖父容器作用域中的实例，因此后者最后释放
+	if s.parent != nil {
+		result = append(result, s.parent.Close()...)
+	}
+	return result
+}
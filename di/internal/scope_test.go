@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type scopeTestConfig struct{}
+
+type scopeTestUnitOfWork struct {
+	Config *scopeTestConfig
+}
+
+type scopeTestRepo struct {
+	UoW *scopeTestUnitOfWork
+}
+
+func mustRegister(t *testing.T, e *Engine, lifetime ServiceLifetime, factory interface{}) {
+	t.Helper()
+	if err := e.Register(&Registration{Lifetime: lifetime, Factory: factory}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+}
+
+func TestScopedInstancesAreCachedPerScope(t *testing.T) {
+	e := NewEngine()
+	mustRegister(t, e, Singleton, func() *scopeTestConfig { return &scopeTestConfig{} })
+	mustRegister(t, e, Scoped, func(c *scopeTestConfig) *scopeTestUnitOfWork { return &scopeTestUnitOfWork{Config: c} })
+	mustRegister(t, e, Scoped, func(u *scopeTestUnitOfWork) *scopeTestRepo { return &scopeTestRepo{UoW: u} })
+	if err := e.Compile(); err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	uowType := reflect.TypeOf(&scopeTestUnitOfWork{})
+	repoType := reflect.TypeOf(&scopeTestRepo{})
+
+	scope1, _ := e.NewScope()
+	scope2, _ := e.NewScope()
+
+	a, err := scope1.Resolve(uowType, "")
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	b, _ := scope1.Resolve(uowType, "")
+	c, _ := scope2.Resolve(uowType, "")
+	if a != b {
+		t.Error("expected same instance within a scope")
+	}
+	if a == c {
+		t.Error("expected different instances across scopes")
+	}
+
+	repo, _ := scope1.Resolve(repoType, "")
+	if repo.(*scopeTestRepo).UoW != a {
+		t.Error("expected scoped dependency to be shared within the scope")
+	}
+
+	// LIFO: repo 最后创建，最先释放
+	closed := scope1.Close()
+	if len(closed) != 2 || closed[0] != repo || closed[1] != a {
+		t.Errorf("expected LIFO order [repo, uow], got %v", closed)
+	}
+	if _, err := scope1.Resolve(uowType, ""); err == nil {
+		t.Error("expected error resolving from a closed scope")
+	}
+}
+
+func TestScopedCannotBeResolvedFromRoot(t *testing.T) {
+	e := NewEngine()
+	mustRegister(t, e, Scoped, func() *scopeTestUnitOfWork { return &scopeTestUnitOfWork{} })
+	if err := e.Compile(); err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	_, err := e.Resolve(reflect.TypeOf(&scopeTestUnitOfWork{}), "")
+	if err == nil || !strings.Contains(err.Error(), "root provider") {
+		t.Fatalf("expected root provider error, got %v", err)
+	}
+}
+
+func TestSingletonCannotCaptureScoped(t *testing.T) {
+	e := NewEngine()
+	mustRegister(t, e, Scoped, func() *scopeTestUnitOfWork { return &scopeTestUnitOfWork{} })
+	mustRegister(t, e, Singleton, func(u *scopeTestUnitOfWork) *scopeTestRepo { return &scopeTestRepo{UoW: u} })
+
+	err := e.Compile()
+	if err == nil {
+		t.Fatal("expected captive dependency error")
+	}
+	if !strings.Contains(err.Error(), "└─") || !strings.Contains(err.Error(), "❌") {
+		t.Errorf("expected dependency tree in error, got: %v", err)
+	}
+}
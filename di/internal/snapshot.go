@@ -0,0 +1,91 @@
+package internal
+
+// GraphSnapshot 依赖图的只读快照，供导出和诊断使用
+type GraphSnapshot struct {
+	Nodes []SnapshotNode
+	Edges []SnapshotEdge
+}
+
+// SnapshotNode 快照中的服务节点，每个注册键一个节点（按首次注册顺序）
+type SnapshotNode struct {
+	Key      RegistrationKey
+	Name     string // 显示名称，命名服务附带键名
+	Lifetime ServiceLifetime
+	Alias    bool // 接口别名（AddAs 绑定的接口）
+	Missing  bool // 被依赖但未注册
+}
+
+// SnapshotEdge 快照中的依赖边，From/To 为 Nodes 下标
+type SnapshotEdge struct {
+	From     int
+	To       int
+	Optional bool // 可选依赖
+	All      bool // []T 依赖
+	Alias    bool // 接口别名指向具体类型
+	Lazy     bool // Lazy[T] 延迟依赖
+	Func     bool // func() T 工厂依赖
+}
+
+// Snapshot 按注册顺序返回依赖图快照
+// 同一键的多次注册合并为一个节点，生命周期取当前生效的注册；装饰器的依赖归入被装饰的键
+func (e *Engine) Snapshot() GraphSnapshot {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var snap GraphSnapshot
+	index := make(map[RegistrationKey]int)
+	node := func(key RegistrationKey) int {
+		if i, exists := index[key]; exists {
+			return i
+		}
+		n := SnapshotNode{Key: key, Name: formatKey(key), Missing: true}
+		if reg, exists := e.lookup(key); exists {
+			n.Lifetime = reg.Lifetime
+			n.Alias = reg.Key() != key
+			n.Missing = false
+		}
+		index[key] = len(snap.Nodes)
+		snap.Nodes = append(snap.Nodes, n)
+		return index[key]
+	}
+
+	// 先按注册顺序创建所有节点，保证节点顺序不受依赖关系影响
+	for _, b := range e.bindings {
+		node(b.key)
+	}
+
+	type edgeKey struct {
+		from, to int
+		all      bool
+	}
+	seen := make(map[edgeKey]bool)
+	addEdge := func(edge SnapshotEdge) {
+		k := edgeKey{edge.From, edge.To, edge.All}
+		if seen[k] {
+			return
+		}
+		seen[k] = true
+		snap.Edges = append(snap.Edges, edge)
+	}
+
+	for _, b := range e.bindings {
+		from := index[b.key]
+		if b.reg.Key() != b.key {
+			addEdge(SnapshotEdge{From: from, To: node(b.reg.Key()), Alias: true})
+			continue
+		}
+		for layer := b.reg; layer != nil; layer = layer.Inner {
+			for _, d := range layer.Dependencies {
+				if !d.All {
+					addEdge(SnapshotEdge{From: from, To: node(d.Key), Optional: d.Optional, Lazy: d.Lazy, Func: d.Func})
+					continue
+				}
+				for _, target := range e.dependencyTargets(d) {
+					addEdge(SnapshotEdge{From: from, To: node(target.key), All: true})
+				}
+			}
+		}
+	}
+
+	return snap
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError 汇总编译期校验发现的所有问题
+type ValidationError struct {
+	Errors []error
+}
+
+func (v *ValidationError) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "service validation failed with %d error(s):", len(v.Errors))
+	for i, err := range v.Errors {
+		fmt.Fprintf(&builder, "\n[%d] %v", i+1, err)
+	}
+	return builder.String()
+}
+
+// Unwrap 支持 errors.Is / errors.As 匹配其中任意一个错误
+func (v *ValidationError) Unwrap() []error {
+	return v.Errors
+}
+
+// validateAll 校验依赖图而不调用任何构造函数（调用方已持有锁）
+// 按类别依次收集：装饰器目标缺失、依赖缺失、循环依赖、生命周期违规
+func (e *Engine) validateAll(decoratorErrs []error) error {
+	errs := append([]error(nil), decoratorErrs...)
+	errs = append(errs, e.missingDependencies()...)
+	errs = append(errs, e.graph.Cycles()...)
+	errs = append(errs, e.lifetimeViolations()...)
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// missingDependencies 按注册顺序返回所有未注册的必需依赖
+func (e *Engine) missingDependencies() []error {
+	var errs []error
+	for _, b := range e.bindings {
+		if b.reg.Key() != b.key {
+			continue
+		}
+		for layer := b.reg; layer != nil; layer = layer.Inner {
+			for _, d := range layer.Dependencies {
+				if d.Optional || d.All {
+					continue
+				}
+				if _, exists := e.lookup(d.Key); exists {
+					continue
+				}
+				tree := formatDependencyTree([]string{formatKey(b.key)}, formatKey(d.Key))
+				errs = append(errs, fmt.Errorf("service '%s' not found:%s\n  Cause: service not registered",
+					formatKey(d.Key), tree))
+			}
+		}
+	}
+	return errs
+}
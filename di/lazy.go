@@ -0,0 +1,27 @@
+package di
+
+import "github.com/gocrud/csgo/di/internal"
+
+// Lazy is a dependency that is resolved on the first call to Value and cached afterwards.
+// Inject it as a constructor parameter (or parameter object field) to defer construction
+// of an expensive service, or to break a legitimate cycle:
+//
+//	func NewEventBus(subscribers di.Lazy[*AuditSubscriber]) *EventBus { ... }
+//	func NewAuditSubscriber(bus *EventBus) *AuditSubscriber { ... }
+//
+// A plain func() T parameter works the same way but resolves on every call, so a
+// singleton can use it to create fresh transient instances:
+//
+//	func NewWorker(newJob func() *Job) *Worker { ... }
+//
+// Deferred dependencies are not construction edges: they are ignored by cycle detection
+// and build ordering, but still take part in missing-service and captive-dependency
+// validation. Value panics if the service cannot be resolved. Dependencies created inside
+// a scope resolve from that scope. Value may be called inside the owner's constructor,
+// including singletons created at Build; if the call leads back to the service being
+// constructed, it panics with a circular dependency error instead of deadlocking.
+//
+// Singletons are still created at Build unless ServiceProviderOptions.LazySingletons is set;
+// combine the two to skip construction of services a code path never uses.
+// A func() T type that is itself registered as a service is injected as that service.
+type Lazy[T any] = internal.Lazy[T]
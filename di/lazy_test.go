@@ -0,0 +1,201 @@
+package di_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type LazyEventBus struct {
+	Subscriber di.Lazy[*LazySubscriber]
+}
+
+type LazySubscriber struct {
+	Bus *LazyEventBus
+}
+
+type ExpensiveIndex struct{}
+
+type LazyCommand struct {
+	Index di.Lazy[*ExpensiveIndex]
+}
+
+type LazyJob struct {
+	ID int
+}
+
+type LazyWorker struct {
+	NewJob func() *LazyJob
+}
+
+// TestLazyBreaksCycle tests that a Lazy edge is not treated as a circular dependency
+func TestLazyBreaksCycle(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(func(s di.Lazy[*LazySubscriber]) *LazyEventBus { return &LazyEventBus{Subscriber: s} })
+	services.Add(func(b *LazyEventBus) *LazySubscriber { return &LazySubscriber{Bus: b} })
+
+	provider := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ValidateOnBuild: true})
+
+	bus := di.Get[*LazyEventBus](provider)
+	sub := bus.Subscriber.Value()
+	if sub.Bus != bus {
+		t.Error("expected subscriber to reference the same bus")
+	}
+	if bus.Subscriber.Value() != di.Get[*LazySubscriber](provider) {
+		t.Error("expected Lazy to resolve the singleton instance")
+	}
+}
+
+// TestLazyDefersConstruction tests that an unused Lazy dependency is never constructed
+func TestLazyDefersConstruction(t *testing.T) {
+	created := 0
+	services := di.NewServiceCollection()
+	services.Add(func() *ExpensiveIndex { created++; return &ExpensiveIndex{} })
+	services.Add(func(idx di.Lazy[*ExpensiveIndex]) *LazyCommand { return &LazyCommand{Index: idx} })
+
+	provider := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{LazySingletons: true})
+
+	cmd := di.Get[*LazyCommand](provider)
+	if created != 0 {
+		t.Fatalf("expected index not to be created yet, created %d", created)
+	}
+	cmd.Index.Value()
+	cmd.Index.Value()
+	if created != 1 {
+		t.Errorf("expected index to be created once, created %d", created)
+	}
+}
+
+// TestFuncFactoryCreatesTransients tests that func() T resolves on every call
+func TestFuncFactoryCreatesTransients(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddTransient(func() *LazyJob { return &LazyJob{} })
+	services.Add(func(newJob func() *LazyJob) *LazyWorker { return &LazyWorker{NewJob: newJob} })
+
+	// A singleton may hold a factory for transients without being a captive dependency
+	provider := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ValidateOnBuild: true})
+
+	worker := di.Get[*LazyWorker](provider)
+	if a, b := worker.NewJob(), worker.NewJob(); a == nil || a == b {
+		t.Error("expected a new job on every call")
+	}
+}
+
+// TestLazyScopedCaptiveDependency tests that a singleton cannot capture a scoped service through Lazy
+func TestLazyScopedCaptiveDependency(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddScoped(func() *ExpensiveIndex { return &ExpensiveIndex{} })
+	services.Add(func(idx di.Lazy[*ExpensiveIndex]) *LazyCommand { return &LazyCommand{Index: idx} })
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "captive dependency") {
+			t.Fatalf("expected captive dependency panic, got %v", r)
+		}
+	}()
+	di.BuildServiceProvider(services)
+}
+
+// TestLazyResolvesFromScope tests that Lazy dependencies created in a scope resolve from that scope
+func TestLazyResolvesFromScope(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddScoped(func() *ExpensiveIndex { return &ExpensiveIndex{} })
+	services.AddScoped(func(idx di.Lazy[*ExpensiveIndex]) *LazyCommand { return &LazyCommand{Index: idx} })
+	provider := di.BuildServiceProvider(services)
+
+	scope := provider.CreateScope()
+	defer scope.Dispose()
+
+	cmd := di.Get[*LazyCommand](scope.ServiceProvider())
+	if cmd.Index.Value() != di.Get[*ExpensiveIndex](scope.ServiceProvider()) {
+		t.Error("expected Lazy to resolve the scoped instance of its scope")
+	}
+}
+
+// TestRegisteredFuncTypeIsInjectedAsService tests that a registered func() T is not treated as a factory
+func TestRegisteredFuncTypeIsInjectedAsService(t *testing.T) {
+	job := &LazyJob{}
+	services := di.NewServiceCollection()
+	services.AddInstance(func() *LazyJob { return job })
+	services.Add(func(newJob func() *LazyJob) *LazyWorker { return &LazyWorker{NewJob: newJob} })
+	provider := di.BuildServiceProvider(services)
+
+	if di.Get[*LazyWorker](provider).NewJob() != job {
+		t.Error("expected the registered function to be injected")
+	}
+}
+
+// TestFuncCalledDuringBuild tests that a singleton constructor can call an injected func() T while the provider is built
+func TestFuncCalledDuringBuild(t *testing.T) {
+	nextID := 0
+	services := di.NewServiceCollection()
+	services.AddTransient(func() *LazyJob { nextID++; return &LazyJob{ID: nextID} })
+	services.Add(func(newJob func() *LazyJob) *LazyWorker {
+		if job := newJob(); job.ID != 1 {
+			t.Errorf("expected first job during build, got %d", job.ID)
+		}
+		return &LazyWorker{NewJob: newJob}
+	})
+
+	provider := di.BuildServiceProvider(services)
+
+	if job := di.Get[*LazyWorker](provider).NewJob(); job.ID != 2 {
+		t.Errorf("expected a new job after build, got %d", job.ID)
+	}
+}
+
+// TestDeferredCalledInsideScopedConstructor tests that Lazy and func() T can be used inside a scoped constructor without deadlocking
+func TestDeferredCalledInsideScopedConstructor(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddScoped(func() *ExpensiveIndex { return &ExpensiveIndex{} })
+	services.AddTransient(func() *LazyJob { return &LazyJob{} })
+	services.AddScoped(func(idx di.Lazy[*ExpensiveIndex], newJob func() *LazyJob) *LazyCommand {
+		idx.Value()
+		newJob()
+		return &LazyCommand{Index: idx}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	scope := provider.CreateScope()
+	defer scope.Dispose()
+
+	done := make(chan *LazyCommand, 1)
+	go func() { done <- di.Get[*LazyCommand](scope.ServiceProvider()) }()
+
+	select {
+	case cmd := <-done:
+		if cmd.Index.Value() != di.Get[*ExpensiveIndex](scope.ServiceProvider()) {
+			t.Error("expected Lazy to resolve the scoped instance of its scope")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock: resolving inside a scoped constructor did not return")
+	}
+}
+
+// TestLazyCycleInsideConstructor tests that a Lazy call leading back to the service under construction reports a cycle
+func TestLazyCycleInsideConstructor(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(func(s di.Lazy[*LazySubscriber]) *LazyEventBus {
+		s.Value()
+		return &LazyEventBus{Subscriber: s}
+	})
+	services.Add(func(b *LazyEventBus) *LazySubscriber { return &LazySubscriber{Bus: b} })
+
+	done := make(chan any, 1)
+	go func() {
+		defer func() { done <- recover() }()
+		di.BuildServiceProvider(services)
+	}()
+
+	select {
+	case r := <-done:
+		if r == nil || !strings.Contains(fmt.Sprint(r), "circular dependency detected") {
+			t.Fatalf("expected circular dependency panic, got %v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock: cyclic Lazy call inside a constructor did not return")
+	}
+}
@@ -0,0 +1,100 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type disposableService struct {
+	name     string
+	disposed *[]string
+}
+
+func (s *disposableService) Dispose() error {
+	*s.disposed = append(*s.disposed, s.name)
+	return nil
+}
+
+type scopedConsumer struct {
+	Dep *disposableService
+}
+
+// TestScopedLifetime tests that scoped services are cached per scope and disposed with it
+func TestScopedLifetime(t *testing.T) {
+	var disposed []string
+	services := di.NewServiceCollection()
+	services.AddScoped(func() *disposableService {
+		return &disposableService{name: "scoped", disposed: &disposed}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	scope1 := provider.CreateScope()
+	scope2 := provider.CreateScope()
+
+	a := di.Get[*disposableService](scope1.ServiceProvider())
+	b := di.Get[*disposableService](scope1.ServiceProvider())
+	c := di.Get[*disposableService](scope2.ServiceProvider())
+	if a != b {
+		t.Error("Expected same instance within a scope")
+	}
+	if a == c {
+		t.Error("Expected different instances across scopes")
+	}
+
+	if _, ok := di.TryGet[*disposableService](provider); ok {
+		t.Error("Expected scoped service to be unresolvable from the root provider")
+	}
+
+	if err := scope1.Dispose(); err != nil {
+		t.Fatalf("Unexpected dispose error: %v", err)
+	}
+	if len(disposed) != 1 {
+		t.Errorf("Expected 1 disposed service, got %d", len(disposed))
+	}
+}
+
+// TestTransientLifetime tests that transient services are created on every resolve
+func TestTransientLifetime(t *testing.T) {
+	var disposed []string
+	services := di.NewServiceCollection()
+	services.AddTransient(func() *disposableService {
+		return &disposableService{name: "transient", disposed: &disposed}
+	})
+	services.AddScoped(func(d *disposableService) *scopedConsumer {
+		return &scopedConsumer{Dep: d}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	if di.Get[*disposableService](provider) == di.Get[*disposableService](provider) {
+		t.Error("Expected a new transient instance on every resolve")
+	}
+
+	scope := provider.CreateScope()
+	consumer := di.Get[*scopedConsumer](scope.ServiceProvider())
+	if consumer.Dep == nil {
+		t.Fatal("Expected transient dependency to be injected")
+	}
+	scope.Dispose()
+	if len(disposed) != 1 {
+		t.Errorf("Expected transient created in scope to be disposed, got %d", len(disposed))
+	}
+}
+
+// TestNamedLifetimes tests keyed scoped and transient registrations
+func TestNamedLifetimes(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddNamedScoped("tenant", func() *TestService { return &TestService{Value: "scoped"} })
+	services.AddNamedTransient("temp", func() *TestService { return &TestService{Value: "transient"} })
+	provider := di.BuildServiceProvider(services)
+
+	scope := provider.CreateScope()
+	defer scope.Dispose()
+
+	if svc := di.GetNamed[*TestService](scope.ServiceProvider(), "tenant"); svc.Value != "scoped" {
+		t.Errorf("Expected scoped value, got '%s'", svc.Value)
+	}
+	if svc := di.GetNamed[*TestService](provider, "temp"); svc.Value != "transient" {
+		t.Errorf("Expected transient value, got '%s'", svc.Value)
+	}
+}
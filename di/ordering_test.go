@@ -0,0 +1,77 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type IStep interface {
+	Name() string
+}
+
+type step string
+
+func (s step) Name() string { return string(s) }
+
+func newStep(name string) func() IStep {
+	return func() IStep { return step(name) }
+}
+
+// TestGetAllRegistrationOrder tests that GetAll returns services in registration order
+func TestGetAllRegistrationOrder(t *testing.T) {
+	expected := []string{"a", "b", "named", "c", "d", "e"}
+
+	for run := 0; run < 20; run++ {
+		services := di.NewServiceCollection()
+		services.Add(newStep("a"))
+		services.Add(newStep("b"))
+		services.AddNamed("named", newStep("named"))
+		services.Add(newStep("c"))
+		services.Add(newStep("d"))
+		services.Add(newStep("e"))
+		provider := di.BuildServiceProvider(services)
+
+		all := di.GetAll[IStep](provider)
+		if len(all) != len(expected) {
+			t.Fatalf("Expected %d services, got %d", len(expected), len(all))
+		}
+		for i, s := range all {
+			if s.Name() != expected[i] {
+				t.Fatalf("Run %d: expected %v at index %d, got %s", run, expected[i], i, s.Name())
+			}
+		}
+	}
+}
+
+// TestGetReturnsLastRegistration tests that a single resolve returns the last unkeyed registration
+func TestGetReturnsLastRegistration(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(newStep("first"))
+	services.Add(newStep("second"))
+	provider := di.BuildServiceProvider(services)
+
+	if got := di.Get[IStep](provider).Name(); got != "second" {
+		t.Errorf("Expected last registration 'second', got '%s'", got)
+	}
+}
+
+type stepList struct {
+	di.In
+	Steps []IStep
+}
+
+// TestSliceInjectionRegistrationOrder tests that []T parameter object fields keep registration order
+func TestSliceInjectionRegistrationOrder(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(newStep("x"))
+	services.Add(newStep("y"))
+	services.Add(newStep("z"))
+	services.Add(func(p stepList) []IStep { return p.Steps })
+	provider := di.BuildServiceProvider(services)
+
+	steps := di.Get[[]IStep](provider)
+	if len(steps) != 3 || steps[0].Name() != "x" || steps[2].Name() != "z" {
+		t.Errorf("Expected [x y z], got %v", steps)
+	}
+}
@@ -0,0 +1,115 @@
+package di_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type ParallelCert struct{}
+type ParallelCache struct{}
+type ParallelIndex struct{}
+type ParallelApp struct {
+	Cert  *ParallelCert
+	Cache *ParallelCache
+	Index *ParallelIndex
+}
+
+// TestParallelSingletonsConstructIndependentBranches tests that independent singletons are built concurrently
+func TestParallelSingletonsConstructIndependentBranches(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(3)
+	var running, peak atomic.Int32
+
+	// Each constructor waits until all three are running, which only succeeds if they run concurrently
+	slow := func() {
+		if n := running.Add(1); n > peak.Load() {
+			peak.Store(n)
+		}
+		defer running.Add(-1)
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	}
+
+	services := di.NewServiceCollection()
+	services.Add(func(c *ParallelCert, k *ParallelCache, i *ParallelIndex) *ParallelApp {
+		return &ParallelApp{Cert: c, Cache: k, Index: i}
+	})
+	services.Add(func() *ParallelCert { slow(); return &ParallelCert{} })
+	services.Add(func() *ParallelCache { slow(); return &ParallelCache{} })
+	services.Add(func() *ParallelIndex { slow(); return &ParallelIndex{} })
+
+	provider := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ParallelSingletons: 4})
+
+	if peak.Load() != 3 {
+		t.Errorf("expected 3 constructors to run concurrently, peak was %d", peak.Load())
+	}
+	app := di.Get[*ParallelApp](provider)
+	if app.Cert != di.Get[*ParallelCert](provider) || app.Cache == nil || app.Index == nil {
+		t.Error("expected the dependent singleton to receive the shared instances")
+	}
+}
+
+// TestParallelSingletonsRespectWorkerLimit tests that the worker pool bounds concurrency
+func TestParallelSingletonsRespectWorkerLimit(t *testing.T) {
+	var running, peak atomic.Int32
+	work := func() {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+	}
+
+	services := di.NewServiceCollection()
+	services.Add(func() *ParallelCert { work(); return &ParallelCert{} })
+	services.Add(func() *ParallelCache { work(); return &ParallelCache{} })
+	services.Add(func() *ParallelIndex { work(); return &ParallelIndex{} })
+	di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ParallelSingletons: 2})
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent constructors, got %d", peak.Load())
+	}
+}
+
+// TestParallelSingletonsDeterministicError tests that the reported error matches serial construction
+func TestParallelSingletonsDeterministicError(t *testing.T) {
+	build := func(parallel int) (msg string) {
+		services := di.NewServiceCollection()
+		// The first singleton fails slowly, the second fails immediately
+		services.Add(func() (*ParallelCert, error) {
+			time.Sleep(10 * time.Millisecond)
+			return nil, errors.New("cert failed")
+		})
+		services.Add(func() (*ParallelCache, error) { return nil, errors.New("cache failed") })
+		services.Add(func() *ParallelIndex { return &ParallelIndex{} })
+
+		defer func() { msg, _ = recover().(string) }()
+		di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ParallelSingletons: parallel})
+		return ""
+	}
+
+	serial := build(0)
+	if !strings.Contains(serial, "cert failed") {
+		t.Fatalf("expected serial build to fail on the certificate, got %q", serial)
+	}
+	for i := 0; i < 10; i++ {
+		if got := build(4); got != serial {
+			t.Fatalf("expected parallel error to match serial error:\n%s\ngot:\n%s", serial, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package di
+
+import "github.com/gocrud/csgo/di/internal"
+
+// In is a marker that turns a constructor's struct parameter into a parameter object.
+// Every exported field of a struct that embeds In is resolved from the container
+// individually, which keeps constructors with many dependencies readable:
+//
+//	type RepoParams struct {
+//	    di.In
+//	    Primary *sql.DB   `di:"name=primary"`   // named service
+//	    Replica *sql.DB   `di:"name=replica"`
+//	    Cache   ICache    `di:"optional"`        // zero value if not registered
+//	    Plugins []IPlugin                        // every registration, like di.GetAll
+//	}
+//
+//	func NewUserRepo(p RepoParams) *UserRepo { ... }
+//
+// Supported `di` tag options (comma separated):
+//   - name=<key>: resolve the named service registered with AddNamed
+//   - optional:   leave the field zero instead of failing Build when nothing is registered
+//
+// Slice fields []T receive all registrations of T (an empty slice if there are none).
+// Keyed and slice dependencies are part of the compiled dependency graph, so cycle
+// detection and missing-service errors report the exact named service.
+type In = internal.In
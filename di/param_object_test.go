@@ -0,0 +1,189 @@
+package di_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type Database struct {
+	Name string
+}
+
+type RepoParams struct {
+	di.In
+	Primary *Database `di:"name=primary"`
+	Replica *Database `di:"name=replica"`
+	Service *TestService
+}
+
+type UserRepo struct {
+	Primary *Database
+	Replica *Database
+	Service *TestService
+}
+
+func NewUserRepo(p RepoParams) *UserRepo {
+	return &UserRepo{Primary: p.Primary, Replica: p.Replica, Service: p.Service}
+}
+
+// TestParamObjectNamedDependencies tests keyed injection through a parameter object
+func TestParamObjectNamedDependencies(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddNamed("primary", func() *Database { return &Database{Name: "primary"} })
+	services.AddNamed("replica", func() *Database { return &Database{Name: "replica"} })
+	services.Add(NewTestService)
+	services.Add(NewUserRepo)
+	provider := di.BuildServiceProvider(services)
+
+	repo := di.Get[*UserRepo](provider)
+	if repo.Primary.Name != "primary" {
+		t.Errorf("Expected primary database, got '%s'", repo.Primary.Name)
+	}
+	if repo.Replica.Name != "replica" {
+		t.Errorf("Expected replica database, got '%s'", repo.Replica.Name)
+	}
+	if repo.Service == nil {
+		t.Error("Expected untagged field to be injected")
+	}
+	if repo.Primary != di.GetNamed[*Database](provider, "primary") {
+		t.Error("Expected the named singleton instance")
+	}
+}
+
+// TestParamObjectMissingNamedDependency tests that errors name the missing keyed service
+func TestParamObjectMissingNamedDependency(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddNamed("primary", func() *Database { return &Database{Name: "primary"} })
+	services.Add(NewTestService)
+	services.Add(NewUserRepo)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for missing named dependency")
+		}
+		errStr := r.(string)
+		if !strings.Contains(errStr, "Database(replica)") {
+			t.Errorf("Error message missing keyed service name: %s", errStr)
+		}
+	}()
+
+	di.BuildServiceProvider(services)
+}
+
+// TestParamObjectNamedCycle tests cycle detection across keyed dependencies
+func TestParamObjectNamedCycle(t *testing.T) {
+	type Node struct{}
+	type NodeParams struct {
+		di.In
+		Next *Node `di:"name=b"`
+	}
+	type OtherParams struct {
+		di.In
+		Next *Node `di:"name=a"`
+	}
+
+	services := di.NewServiceCollection()
+	services.AddNamed("a", func(p NodeParams) *Node { return &Node{} })
+	services.AddNamed("b", func(p OtherParams) *Node { return &Node{} })
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for circular dependency")
+		}
+		if errStr := r.(string); !strings.Contains(errStr, "circular dependency detected") {
+			t.Errorf("Unexpected error: %s", errStr)
+		}
+	}()
+
+	di.BuildServiceProvider(services)
+}
+
+// TestParamObjectInvalidTag tests that unknown tag options are rejected at registration
+func TestParamObjectInvalidTag(t *testing.T) {
+	type BadParams struct {
+		di.In
+		Service *TestService `di:"nmae=x"`
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic for invalid di tag")
+		}
+	}()
+
+	services := di.NewServiceCollection()
+	services.Add(func(p BadParams) *UserRepo { return &UserRepo{} })
+}
+
+type IPlugin interface {
+	Name() string
+}
+
+type plugin struct {
+	name string
+}
+
+func (p *plugin) Name() string { return p.name }
+
+type OptionalParams struct {
+	di.In
+	Service *TestService
+	Config  *ConfigService `di:"optional"`
+	Replica *Database      `di:"name=replica,optional"`
+	Plugins []IPlugin
+}
+
+type Aggregator struct {
+	Params OptionalParams
+}
+
+// TestParamObjectOptionalAndSlice tests optional fields and []T fields
+func TestParamObjectOptionalAndSlice(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(NewTestService)
+	services.AddNamed("a", func() IPlugin { return &plugin{name: "a"} })
+	services.AddNamed("b", func() IPlugin { return &plugin{name: "b"} })
+	services.Add(func(p OptionalParams) *Aggregator { return &Aggregator{Params: p} })
+	provider := di.BuildServiceProvider(services)
+
+	agg := di.Get[*Aggregator](provider)
+	if agg.Params.Service == nil {
+		t.Error("Expected required field to be injected")
+	}
+	if agg.Params.Config != nil || agg.Params.Replica != nil {
+		t.Error("Expected unregistered optional fields to stay zero")
+	}
+	if len(agg.Params.Plugins) != 2 {
+		t.Fatalf("Expected 2 plugins, got %d", len(agg.Params.Plugins))
+	}
+}
+
+// TestParamObjectEmptySlice tests that []T fields are empty when nothing is registered
+func TestParamObjectEmptySlice(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(NewTestService)
+	services.Add(func(p OptionalParams) *Aggregator { return &Aggregator{Params: p} })
+	provider := di.BuildServiceProvider(services)
+
+	if plugins := di.Get[*Aggregator](provider).Params.Plugins; len(plugins) != 0 {
+		t.Errorf("Expected no plugins, got %d", len(plugins))
+	}
+}
+
+// TestParamObjectOptionalRegistered tests that registered optional fields are injected
+func TestParamObjectOptionalRegistered(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(NewTestService)
+	services.Add(NewConfigService)
+	services.Add(func() *ConfigService { return &ConfigService{Port: 9090} })
+	services.Add(func(p OptionalParams) *Aggregator { return &Aggregator{Params: p} })
+	provider := di.BuildServiceProvider(services)
+
+	if cfg := di.Get[*Aggregator](provider).Params.Config; cfg == nil || cfg.Port != 9090 {
+		t.Errorf("Expected registered optional field to be injected, got %v", cfg)
+	}
+}
@@ -0,0 +1,109 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type fakeGreeter struct{}
+
+func (fakeGreeter) Greet() string { return "fake" }
+
+// TestReplaceKeepsLifetimeAndDecorators tests that Replace swaps the implementation only
+func TestReplaceKeepsLifetimeAndDecorators(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(func() IGreeter { return &greeter{} })
+	services.Add(func() IGreeter { return &greeter{} })
+	services.Decorate(func(inner IGreeter) IGreeter { return &suffixGreeter{inner: inner, suffix: "!"} })
+	services.AddNamed("keep", func() IGreeter { return &greeter{} })
+
+	services.Replace(func() IGreeter { return fakeGreeter{} })
+	provider := di.BuildServiceProvider(services)
+
+	if got := di.Get[IGreeter](provider).Greet(); got != "fake!" {
+		t.Errorf("Expected decorated fake 'fake!', got '%s'", got)
+	}
+	if all := di.GetAll[IGreeter](provider); len(all) != 2 {
+		t.Errorf("Expected the replacement plus the untouched named service, got %d", len(all))
+	}
+	if got := di.GetNamed[IGreeter](provider, "keep").Greet(); got != "hello" {
+		t.Errorf("Expected named registration to be untouched, got '%s'", got)
+	}
+}
+
+// TestReplaceScoped tests that Replace keeps the scoped lifetime
+func TestReplaceScoped(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddScoped(NewTestService)
+	services.Replace(func() *TestService { return &TestService{Value: "fake"} })
+	provider := di.BuildServiceProvider(services)
+
+	if _, ok := di.TryGet[*TestService](provider); ok {
+		t.Error("Expected replacement to stay scoped")
+	}
+	scope := provider.CreateScope()
+	defer scope.Dispose()
+	if got := di.Get[*TestService](scope.ServiceProvider()).Value; got != "fake" {
+		t.Errorf("Expected 'fake', got '%s'", got)
+	}
+}
+
+// TestReplaceNamed tests replacing a keyed registration
+func TestReplaceNamed(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddNamed("primary", func() *Database { return &Database{Name: "real"} })
+	services.ReplaceNamed("primary", func() *Database { return &Database{Name: "fake"} })
+	provider := di.BuildServiceProvider(services)
+
+	if got := di.GetNamed[*Database](provider, "primary").Name; got != "fake" {
+		t.Errorf("Expected 'fake', got '%s'", got)
+	}
+}
+
+// TestReplaceMissingPanics tests that Replace requires an existing registration
+func TestReplaceMissingPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("Expected panic when nothing is registered to replace")
+		}
+	}()
+
+	services := di.NewServiceCollection()
+	services.Replace(NewTestService)
+}
+
+// TestAddOrReplace tests adding when missing and replacing when present
+func TestAddOrReplace(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddOrReplace(func() *TestService { return &TestService{Value: "added"} })
+	services.AddOrReplace(func() *TestService { return &TestService{Value: "replaced"} })
+	provider := di.BuildServiceProvider(services)
+
+	if all := di.GetAll[*TestService](provider); len(all) != 1 || all[0].Value != "replaced" {
+		t.Errorf("Expected a single 'replaced' registration, got %v", all)
+	}
+}
+
+// TestRemoveAll tests removing every registration of a type
+func TestRemoveAll(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddAs(NewMemoryUserRepo, new(IUserReader))
+	services.Add(newStep("a"))
+	services.AddNamed("b", newStep("b"))
+	services.Decorate(func(inner IStep) IStep { return inner })
+
+	di.RemoveAll[IStep](services)
+	di.RemoveAll[IUserReader](services)
+	provider := di.BuildServiceProvider(services)
+
+	if all := di.GetAll[IStep](provider); len(all) != 0 {
+		t.Errorf("Expected no IStep registrations, got %d", len(all))
+	}
+	if _, ok := di.TryGet[IUserReader](provider); ok {
+		t.Error("Expected interface binding to be removed")
+	}
+	if _, ok := di.TryGet[*MemoryUserRepo](provider); !ok {
+		t.Error("Expected concrete registration to remain after removing an interface binding")
+	}
+}
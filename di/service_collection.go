@@ -12,6 +12,7 @@ import (
 // Build、Count 和 GetDescriptors 方法在具体类型上可用，但不在接口中。
 type IServiceCollection interface {
 	// Add 使用构造函数注册单例服务。
+	// 同一类型可以注册多次：Get 返回最后注册的服务，GetAll 按注册顺序返回全部。
 	Add(constructor any) IServiceCollection
 
 	// AddInstance 注册单例实例（预先创建的对象）。
@@ -20,16 +21,59 @@ type IServiceCollection interface {
 	// AddNamed 注册命名单例服务。
 	AddNamed(name string, constructor any) IServiceCollection
 
+	// AddScoped 使用构造函数注册 Scoped 服务（每个作用域一个实例）。
+	AddScoped(constructor any) IServiceCollection
+
+	// AddNamedScoped 注册命名 Scoped 服务。
+	AddNamedScoped(name string, constructor any) IServiceCollection
+
+	// AddTransient 使用构造函数注册 Transient 服务（每次解析创建新实例）。
+	AddTransient(constructor any) IServiceCollection
+
+	// AddNamedTransient 注册命名 Transient 服务。
+	AddNamedTransient(name string, constructor any) IServiceCollection
+
 	// TryAdd 尝试添加单例服务（如果不存在）。
 	TryAdd(constructor any) IServiceCollection
 
 	// AddHostedService 注册托管服务（后台服务）。
 	AddHostedService(constructor any) IServiceCollection
+
+	// AddAs 注册单例服务，并同时以一个或多个接口暴露（共享同一实例）。
+	// serviceTypes 为接口指针，例如 new(IUserReader) 或 (*IUserReader)(nil)。
+	AddAs(constructor any, serviceTypes ...any) IServiceCollection
+
+	// Decorate 为已注册的服务添加装饰器。
+	// 装饰器形如 func(inner T, deps...) T，被装饰的服务类型由第一个参数推断。
+	Decorate(decorator any) IServiceCollection
+
+	// Replace 用新的构造函数替换返回类型的所有非命名注册（必须已存在）。
+	Replace(constructor any) IServiceCollection
+
+	// ReplaceNamed 用新的构造函数替换指定名称的命名注册（必须已存在）。
+	ReplaceNamed(name string, constructor any) IServiceCollection
+
+	// AddOrReplace 替换返回类型的所有非命名注册，不存在时按单例添加。
+	AddOrReplace(constructor any) IServiceCollection
+
+	// AddIf 注册单例服务，条件在构建服务提供者时求值，返回 false 时不注册。
+	AddIf(condition func(ConditionContext) bool, constructor any) IServiceCollection
+
+	// AddWhenEnvironment 注册单例服务，仅在指定环境中生效。
+	AddWhenEnvironment(environment string, constructor any) IServiceCollection
+
+	// AddWhenConfig 注册单例服务，仅当配置键的值为 true 时生效。
+	AddWhenConfig(key string, constructor any) IServiceCollection
+
+	// RemoveAll 移除类型的所有注册（包括命名注册）及其装饰器。
+	// serviceType 为类型指针（new(T) 或 (*T)(nil)）或 reflect.Type。
+	RemoveAll(serviceType any) IServiceCollection
 }
 
 // serviceCollection 是 IServiceCollection 的具体实现。
 type serviceCollection struct {
-	engine *internal.Engine
+	engine     *internal.Engine
+	conditions ConditionContext // 条件注册的求值上下文
 }
 
 // NewServiceCollection 创建一个新的服务集合。
@@ -52,6 +96,17 @@ func BuildServiceProvider(services IServiceCollection) IServiceProvider {
 	return sc.Build()
 }
 
+// BuildServiceProviderWithOptions 使用指定选项从服务集合构建服务提供者。
+// 构建失败时 panic，错误信息包含所有校验问题（启用 ValidateOnBuild 时）。
+func BuildServiceProviderWithOptions(services IServiceCollection, options ServiceProviderOptions) IServiceProvider {
+	sc, ok := services.(*serviceCollection)
+	if !ok {
+		panic("services must be created by NewServiceCollection")
+	}
+
+	return sc.BuildWithOptions(options)
+}
+
 // Add 使用构造函数注册单例服务。
 func (s *serviceCollection) Add(constructor any) IServiceCollection {
 	if err := s.register(constructor, Singleton); err != nil {
@@ -107,7 +162,7 @@ func (s *serviceCollection) AddInstance(instance any) IServiceCollection {
 }
 
 // AddHostedService 注册托管服务。
-// 该服务将在主机启动时启动，在主机停止时停止。
+// 该服务将在主机启动时启动，在主机停止时停止；多个托管服务按注册顺序启动。
 func (s *serviceCollection) AddHostedService(constructor any) IServiceCollection {
 	// 注册为 Singleton（托管服务应该是单例）
 	return s.Add(constructor)
@@ -121,15 +176,246 @@ func (s *serviceCollection) AddNamed(name string, constructor any) IServiceColle
 	return s
 }
 
+// AddScoped 使用构造函数注册 Scoped 服务。
+// Scoped 服务只能从作用域中解析（参见 IServiceProvider.CreateScope），
+// 单例服务不能依赖 Scoped 服务。
+func (s *serviceCollection) AddScoped(constructor any) IServiceCollection {
+	if err := s.register(constructor, Scoped); err != nil {
+		panic(fmt.Sprintf("failed to register scoped service: %v", err))
+	}
+	return s
+}
+
+// AddNamedScoped 注册命名 Scoped 服务。
+func (s *serviceCollection) AddNamedScoped(name string, constructor any) IServiceCollection {
+	if err := s.registerKeyed(constructor, Scoped, name); err != nil {
+		panic(fmt.Sprintf("failed to register named scoped service: %v", err))
+	}
+	return s
+}
+
+// AddTransient 使用构造函数注册 Transient 服务。
+// 每次解析都会调用构造函数创建新实例，单例服务不能依赖 Transient 服务。
+func (s *serviceCollection) AddTransient(constructor any) IServiceCollection {
+	if err := s.register(constructor, Transient); err != nil {
+		panic(fmt.Sprintf("failed to register transient service: %v", err))
+	}
+	return s
+}
+
+// AddNamedTransient 注册命名 Transient 服务。
+func (s *serviceCollection) AddNamedTransient(name string, constructor any) IServiceCollection {
+	if err := s.registerKeyed(constructor, Transient, name); err != nil {
+		panic(fmt.Sprintf("failed to register named transient service: %v", err))
+	}
+	return s
+}
+
+// AddAs 注册单例服务，并同时以给定接口暴露。
+// 具体类型与所有接口解析到同一个实例，无需为每个接口编写转发构造函数：
+//
+//	services.AddAs(NewUserRepo, new(IUserReader), new(IUserWriter))
+//	// di.Get[*UserRepo]、di.Get[IUserReader]、di.Get[IUserWriter] 返回同一实例
+func (s *serviceCollection) AddAs(constructor any, serviceTypes ...any) IServiceCollection {
+	interfaces := make([]reflect.Type, len(serviceTypes))
+	for i, st := range serviceTypes {
+		iface, err := interfaceTypeOf(st)
+		if err != nil {
+			panic(fmt.Sprintf("failed to register service: %v", err))
+		}
+		interfaces[i] = iface
+	}
+
+	reg, err := newRegistration(constructor, Singleton)
+	if err == nil {
+		reg.Interfaces = interfaces
+		err = s.engine.Register(reg)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("failed to register service: %v", err))
+	}
+	return s
+}
+
+// AddAs 注册返回 TImpl 的单例构造函数，并同时以 TService 接口暴露（共享同一实例）。
+//
+//	di.AddAs[*UserRepo, IUserReader](services, NewUserRepo)
+func AddAs[TImpl any, TService any](services IServiceCollection, constructor any) IServiceCollection {
+	implType := reflect.TypeOf((*TImpl)(nil)).Elem()
+	ctorType := reflect.TypeOf(constructor)
+	if ctorType == nil || ctorType.Kind() != reflect.Func || ctorType.NumOut() == 0 || ctorType.Out(0) != implType {
+		panic(fmt.Sprintf("failed to register service: constructor must return %v", implType))
+	}
+	return services.AddAs(constructor, (*TService)(nil))
+}
+
+// Decorate 为已注册的服务添加装饰器。
+// 装饰器的第一个参数接收原始实例（或上一层装饰器的输出），其余参数作为依赖从容器解析，
+// 返回值替换该服务的解析结果。多个装饰器按注册顺序叠加（先注册的在内层）。
+// 装饰器继承被装饰服务的生命周期；原始实例仍由容器跟踪，释放时一并处理。
+// 被装饰的服务可以在 Decorate 之后注册，缺失时 Build 会报错。
+//
+//	services.Decorate(func(inner IUserStore, cache *Cache) IUserStore {
+//	    return &cachedUserStore{inner: inner, cache: cache}
+//	})
+func (s *serviceCollection) Decorate(decorator any) IServiceCollection {
+	if decorator == nil {
+		panic("failed to register decorator: decorator cannot be nil")
+	}
+
+	reg := &internal.Registration{Factory: decorator}
+	if err := s.engine.Decorate(reg, ""); err != nil {
+		panic(fmt.Sprintf("failed to register decorator: %v", err))
+	}
+	return s
+}
+
+// Decorate 为类型 T 的服务添加装饰器，decorator 必须形如 func(inner T, deps...) T。
+//
+//	di.Decorate[IUserStore](services, func(inner IUserStore, log *Logger) IUserStore {
+//	    return &loggingUserStore{inner: inner, log: log}
+//	})
+func Decorate[T any](services IServiceCollection, decorator any) IServiceCollection {
+	serviceType := reflect.TypeOf((*T)(nil)).Elem()
+	decType := reflect.TypeOf(decorator)
+	if decType == nil || decType.Kind() != reflect.Func || decType.NumIn() == 0 || decType.In(0) != serviceType {
+		panic(fmt.Sprintf("failed to register decorator: decorator must take %v as its first parameter", serviceType))
+	}
+	return services.Decorate(decorator)
+}
+
+// Replace 用新的构造函数替换返回类型的所有非命名注册。
+// 主要用于测试：在应用正常的 ConfigureServices 之后把真实服务换成替身。
+//
+// 语义：
+//   - 只影响非命名注册，命名注册保持不变（使用 ReplaceNamed 替换命名注册）
+//   - 新注册沿用被替换注册的生命周期和接口绑定（AddAs）
+//   - 已注册的装饰器继续作用于新注册
+//   - 没有可替换的注册时 panic，避免拼写错误导致替换悄悄失效
+//
+// 示例：
+//
+//	services.Replace(func() IEmailSender { return &fakeEmailSender{} })
+func (s *serviceCollection) Replace(constructor any) IServiceCollection {
+	if err := s.replace(constructor, "", false); err != nil {
+		panic(fmt.Sprintf("failed to replace service: %v", err))
+	}
+	return s
+}
+
+// ReplaceNamed 用新的构造函数替换指定名称的命名注册，语义与 Replace 相同。
+func (s *serviceCollection) ReplaceNamed(name string, constructor any) IServiceCollection {
+	if name == "" {
+		panic("failed to replace named service: serviceKey cannot be empty")
+	}
+	if err := s.replace(constructor, name, false); err != nil {
+		panic(fmt.Sprintf("failed to replace named service: %v", err))
+	}
+	return s
+}
+
+// AddOrReplace 替换返回类型的所有非命名注册；不存在时按单例添加。
+func (s *serviceCollection) AddOrReplace(constructor any) IServiceCollection {
+	if err := s.replace(constructor, "", true); err != nil {
+		panic(fmt.Sprintf("failed to replace service: %v", err))
+	}
+	return s
+}
+
+// RemoveAll 移除类型的所有注册（包括命名注册和接口绑定）及其装饰器。
+//
+//	services.RemoveAll(new(IHostedService))
+func (s *serviceCollection) RemoveAll(serviceType any) IServiceCollection {
+	t, err := serviceTypeOf(serviceType)
+	if err == nil {
+		_, err = s.engine.RemoveType(t)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("failed to remove services: %v", err))
+	}
+	return s
+}
+
+// RemoveAll 移除类型 T 的所有注册（包括命名注册和接口绑定）及其装饰器。
+//
+//	di.RemoveAll[IHostedService](services)
+func RemoveAll[T any](services IServiceCollection) IServiceCollection {
+	return services.RemoveAll((*T)(nil))
+}
+
+// replace 移除键的现有注册并注册新的构造函数。
+func (s *serviceCollection) replace(constructor any, serviceKey string, allowAdd bool) error {
+	reg, err := newRegistration(constructor, Singleton)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.engine.Remove(internal.RegistrationKey{Type: reg.ServiceType, Name: serviceKey})
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 && !allowAdd {
+		return fmt.Errorf("no service of type %v registered", reg.ServiceType)
+	}
+
+	// 沿用最后一个（即当前生效的）被替换注册的生命周期和接口绑定
+	if len(removed) > 0 {
+		last := removed[len(removed)-1]
+		reg.Lifetime = last.Lifetime
+		if last.ServiceType == reg.ServiceType {
+			reg.Interfaces = last.Interfaces
+		}
+	}
+
+	return s.engine.RegisterKeyed(reg, serviceKey)
+}
+
+// serviceTypeOf 从类型指针（new(T) 或 (*T)(nil)）或 reflect.Type 中提取服务类型。
+func serviceTypeOf(serviceType any) (reflect.Type, error) {
+	if t, ok := serviceType.(reflect.Type); ok {
+		return t, nil
+	}
+	t := reflect.TypeOf(serviceType)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("service type must be a pointer to the type, got %T", serviceType)
+	}
+	return t.Elem(), nil
+}
+
+// interfaceTypeOf 从接口指针（new(IFoo) 或 (*IFoo)(nil)）或 reflect.Type 中提取接口类型。
+func interfaceTypeOf(serviceType any) (reflect.Type, error) {
+	t, err := serviceTypeOf(serviceType)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("service type %v is not an interface", t)
+	}
+	return t, nil
+}
+
 // Build 构建服务提供者。
 // 这是具体类型上的便捷方法（不在接口中）。
 // 用法：provider := services.Build()
 func (s *serviceCollection) Build() IServiceProvider {
+	return s.BuildWithOptions(ServiceProviderOptions{})
+}
+
+// BuildWithOptions 使用指定选项构建服务提供者。
+// 这是具体类型上的便捷方法（不在接口中）。
+func (s *serviceCollection) BuildWithOptions(options ServiceProviderOptions) IServiceProvider {
 	provider := &serviceProvider{
-		engine: s.engine,
+		engine:     s.engine,
+		options:    options,
+		conditions: s.conditions,
 	}
 
-	if err := s.engine.Compile(); err != nil {
+	err := s.engine.CompileWithOptions(internal.CompileOptions{
+		ValidateAll:    options.ValidateOnBuild,
+		LazySingletons: options.LazySingletons,
+		MaxParallelism: options.ParallelSingletons,
+	})
+	if err != nil {
 		panic(fmt.Sprintf("failed to build service provider: %v", err))
 	}
 
@@ -139,22 +425,23 @@ func (s *serviceCollection) Build() IServiceProvider {
 // Count 返回已注册服务的数量。
 // 这是具体类型上的诊断方法（不在接口中）。
 func (s *serviceCollection) Count() int {
-	registrations := s.engine.GetAllRegistrations()
-	return len(registrations)
+	return len(s.engine.GetAllRegistrations())
 }
 
-// GetDescriptors 返回所有服务描述符。
+// GetDescriptors 按注册顺序返回所有服务描述符。
 // 这是具体类型上的诊断方法（不在接口中）。
 func (s *serviceCollection) GetDescriptors() []ServiceDescriptor {
 	registrations := s.engine.GetAllRegistrations()
 	descriptors := make([]ServiceDescriptor, 0, len(registrations))
 
-	for key, reg := range registrations {
+	for _, reg := range registrations {
 		descriptor := ServiceDescriptor{
 			ServiceType:        reg.ServiceType,
 			ImplementationType: reg.ImplementationType,
-			Lifetime:           Singleton, // 现在所有服务都是 Singleton
-			ServiceKey:         key.Name,
+			Lifetime:           ServiceLifetime(reg.Lifetime),
+			ServiceKey:         reg.ServiceKey,
+			Factory:            reg.Factory,
+			Interfaces:         reg.Interfaces,
 		}
 		descriptors = append(descriptors, descriptor)
 	}
@@ -162,75 +449,58 @@ func (s *serviceCollection) GetDescriptors() []ServiceDescriptor {
 	return descriptors
 }
 
-// register 是注册单例服务的辅助函数。
+// register 是按指定生命周期注册服务的辅助函数。
 func (s *serviceCollection) register(constructor any, lifetime ServiceLifetime) error {
-	if constructor == nil {
-		return fmt.Errorf("constructor cannot be nil")
-	}
-
-	ctorType := reflect.TypeOf(constructor)
-	if ctorType.Kind() != reflect.Func {
-		return fmt.Errorf("constructor must be a function")
+	reg, err := newRegistration(constructor, lifetime)
+	if err != nil {
+		return err
 	}
+	return s.engine.Register(reg)
+}
 
-	if ctorType.NumOut() == 0 || ctorType.NumOut() > 2 {
-		return fmt.Errorf("constructor must return 1 or 2 values")
+// registerKeyed 是按指定生命周期注册命名服务的辅助函数。
+func (s *serviceCollection) registerKeyed(constructor any, lifetime ServiceLifetime, serviceKey string) error {
+	if serviceKey == "" {
+		return fmt.Errorf("serviceKey cannot be empty")
 	}
 
-	if ctorType.NumOut() == 2 {
-		errorType := reflect.TypeOf((*error)(nil)).Elem()
-		if !ctorType.Out(1).Implements(errorType) {
-			return fmt.Errorf("second return value must be error")
-		}
+	reg, err := newRegistration(constructor, lifetime)
+	if err != nil {
+		return err
 	}
 
-	returnType := ctorType.Out(0)
-
-	reg := &internal.Registration{
-		ServiceType:        returnType,
-		ImplementationType: returnType,
-		Lifetime:           internal.Singleton,
-		Factory:            constructor,
-	}
-
-	return s.engine.Register(reg)
+	// 在引擎中注册带有服务键的服务
+	return s.engine.RegisterKeyed(reg, serviceKey)
 }
 
-// registerKeyed 是注册命名单例服务的辅助函数。
-func (s *serviceCollection) registerKeyed(constructor any, lifetime ServiceLifetime, serviceKey string) error {
+// newRegistration 验证构造函数并创建注册信息。
+func newRegistration(constructor any, lifetime ServiceLifetime) (*internal.Registration, error) {
 	if constructor == nil {
-		return fmt.Errorf("constructor cannot be nil")
-	}
-
-	if serviceKey == "" {
-		return fmt.Errorf("serviceKey cannot be empty")
+		return nil, fmt.Errorf("constructor cannot be nil")
 	}
 
 	ctorType := reflect.TypeOf(constructor)
 	if ctorType.Kind() != reflect.Func {
-		return fmt.Errorf("constructor must be a function")
+		return nil, fmt.Errorf("constructor must be a function")
 	}
 
 	if ctorType.NumOut() == 0 || ctorType.NumOut() > 2 {
-		return fmt.Errorf("constructor must return 1 or 2 values")
+		return nil, fmt.Errorf("constructor must return 1 or 2 values")
 	}
 
 	if ctorType.NumOut() == 2 {
 		errorType := reflect.TypeOf((*error)(nil)).Elem()
 		if !ctorType.Out(1).Implements(errorType) {
-			return fmt.Errorf("second return value must be error")
+			return nil, fmt.Errorf("second return value must be error")
 		}
 	}
 
 	returnType := ctorType.Out(0)
 
-	reg := &internal.Registration{
+	return &internal.Registration{
 		ServiceType:        returnType,
 		ImplementationType: returnType,
-		Lifetime:           internal.Singleton,
+		Lifetime:           internal.ServiceLifetime(lifetime),
 		Factory:            constructor,
-	}
-
-	// 在引擎中注册带有服务键的服务
-	return s.engine.RegisterKeyed(reg, serviceKey)
+	}, nil
 }
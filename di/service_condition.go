@@ -0,0 +1,76 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConditionContext 条件注册求值时可用的环境和配置。
+// hosting.CreateDefaultBuilder 会以主机的 Environment 和 IConfiguration 设置该上下文。
+type ConditionContext interface {
+	// EnvironmentName 返回当前环境名称，例如 "development"。
+	EnvironmentName() string
+
+	// GetConfig 返回配置值，键使用 ":" 分隔，例如 "Features:Cache:Enabled"。
+	GetConfig(key string) string
+}
+
+// errNoConditionContext 服务集合未设置条件上下文
+var errNoConditionContext = errors.New("no condition context; use hosting.CreateDefaultBuilder or di.SetConditionContext")
+
+// SetConditionContext 设置服务集合的条件注册上下文。
+// 条件在构建服务提供者时求值，因此能读取到最终合并后的配置。
+func SetConditionContext(services IServiceCollection, ctx ConditionContext) {
+	sc, ok := services.(*serviceCollection)
+	if !ok {
+		panic("services must be created by NewServiceCollection")
+	}
+	sc.conditions = ctx
+}
+
+// AddIf 注册单例服务，仅当 condition 在构建时返回 true 时生效。
+func (s *serviceCollection) AddIf(condition func(ConditionContext) bool, constructor any) IServiceCollection {
+	if condition == nil {
+		panic("failed to register conditional service: condition cannot be nil")
+	}
+	if err := s.registerWhen(constructor, condition); err != nil {
+		panic(fmt.Sprintf("failed to register conditional service: %v", err))
+	}
+	return s
+}
+
+// AddWhenEnvironment 注册单例服务，仅在指定环境（不区分大小写）中生效。
+//
+//	services.AddWhenEnvironment("development", NewFakeMailer)
+func (s *serviceCollection) AddWhenEnvironment(environment string, constructor any) IServiceCollection {
+	return s.AddIf(func(ctx ConditionContext) bool {
+		return strings.EqualFold(ctx.EnvironmentName(), environment)
+	}, constructor)
+}
+
+// AddWhenConfig 注册单例服务，仅当配置键的值为 true（按 strconv.ParseBool 解析）时生效。
+//
+//	services.AddWhenConfig("Features:Cache:Enabled", NewRedisCache)
+func (s *serviceCollection) AddWhenConfig(key string, constructor any) IServiceCollection {
+	return s.AddIf(func(ctx ConditionContext) bool {
+		enabled, err := strconv.ParseBool(strings.TrimSpace(ctx.GetConfig(key)))
+		return err == nil && enabled
+	}, constructor)
+}
+
+// registerWhen 注册带条件的单例服务，条件在编译时求值。
+func (s *serviceCollection) registerWhen(constructor any, condition func(ConditionContext) bool) error {
+	reg, err := newRegistration(constructor, Singleton)
+	if err != nil {
+		return err
+	}
+	reg.Condition = func() (bool, error) {
+		if s.conditions == nil {
+			return false, errNoConditionContext
+		}
+		return condition(s.conditions), nil
+	}
+	return s.engine.Register(reg)
+}
@@ -0,0 +1,207 @@
+package di
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gocrud/csgo/di/internal"
+)
+
+// GraphFormat 依赖图导出格式。
+type GraphFormat string
+
+const (
+	// GraphFormatDOT Graphviz DOT 格式，可用 `dot -Tsvg` 渲染。
+	GraphFormatDOT GraphFormat = "dot"
+	// GraphFormatMermaid Mermaid 流程图格式，可直接嵌入 Markdown。
+	GraphFormatMermaid GraphFormat = "mermaid"
+	// GraphFormatJSON JSON 格式，包含 nodes 和 edges 两个数组。
+	GraphFormatJSON GraphFormat = "json"
+)
+
+// ExportGraph 以指定格式导出服务提供者的依赖图。
+// 每个节点对应一个服务键（类型 + 名称），附带生命周期；每条边表示一个依赖。
+//
+//	dot, _ := di.ExportGraph(provider, di.GraphFormatDOT)
+//	os.WriteFile("services.dot", []byte(dot), 0644)
+func ExportGraph(provider IServiceProvider, format GraphFormat) (string, error) {
+	var buf bytes.Buffer
+	if err := WriteGraph(&buf, provider, format); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// WriteGraph 以指定格式将服务提供者的依赖图写入 w。
+// 只有 BuildServiceProvider 构建的提供者记录依赖图；其他提供者（例如 csgo-di 生成的 StaticProvider）返回错误。
+func WriteGraph(w io.Writer, provider IServiceProvider, format GraphFormat) error {
+	sp, ok := provider.(*serviceProvider)
+	if !ok {
+		return fmt.Errorf("dependency graph is not available for service provider type %T", provider)
+	}
+	snap := sp.engine.Snapshot()
+
+	switch format {
+	case GraphFormatDOT:
+		return writeDOT(w, snap)
+	case GraphFormatMermaid:
+		return writeMermaid(w, snap)
+	case GraphFormatJSON:
+		return writeGraphJSON(w, snap)
+	default:
+		return fmt.Errorf("unsupported graph format %q", format)
+	}
+}
+
+// nodeLabel 返回节点的显示标签：服务键 + 生命周期
+func nodeLabel(n internal.SnapshotNode) string {
+	switch {
+	case n.Missing:
+		return "missing"
+	case n.Alias:
+		return n.Lifetime.String() + ", alias"
+	default:
+		return n.Lifetime.String()
+	}
+}
+
+// edgeLabel 返回边的标签，普通依赖没有标签
+func edgeLabel(e internal.SnapshotEdge) string {
+	switch {
+	case e.Alias:
+		return "alias"
+	case e.All:
+		return "all"
+	case e.Lazy:
+		return "lazy"
+	case e.Func:
+		return "func"
+	case e.Optional:
+		return "optional"
+	default:
+		return ""
+	}
+}
+
+func writeDOT(w io.Writer, snap internal.GraphSnapshot) error {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var b strings.Builder
+	b.WriteString("digraph services {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for i, n := range snap.Nodes {
+		attrs := ""
+		if n.Missing {
+			attrs = ", style=dashed, color=red"
+		}
+		fmt.Fprintf(&b, "  n%d [label=\"%s\\n(%s)\"%s];\n", i, quote.Replace(n.Name), nodeLabel(n), attrs)
+	}
+	for _, e := range snap.Edges {
+		var attrs []string
+		if label := edgeLabel(e); label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", label))
+		}
+		if e.Optional || e.Alias || e.Lazy || e.Func {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "  n%d -> n%d;\n", e.From, e.To)
+			continue
+		}
+		fmt.Fprintf(&b, "  n%d -> n%d [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMermaid(w io.Writer, snap internal.GraphSnapshot) error {
+	// Mermaid 标签中的引号和尖括号需要使用实体转义
+	quote := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range snap.Nodes {
+		fmt.Fprintf(&b, "  n%d[\"%s<br/>(%s)\"]\n", i, quote.Replace(n.Name), nodeLabel(n))
+	}
+	for _, e := range snap.Edges {
+		arrow := "-->"
+		if e.Optional || e.Alias || e.Lazy || e.Func {
+			arrow = "-.->"
+		}
+		if label := edgeLabel(e); label != "" {
+			arrow += "|" + label + "|"
+		}
+		fmt.Fprintf(&b, "  n%d %s n%d\n", e.From, arrow, e.To)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphJSON JSON 导出格式
+type graphJSON struct {
+	Nodes []graphNodeJSON `json:"nodes"`
+	Edges []graphEdgeJSON `json:"edges"`
+}
+
+type graphNodeJSON struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Key      string `json:"key,omitempty"`
+	Lifetime string `json:"lifetime,omitempty"`
+	Alias    bool   `json:"alias,omitempty"`
+	Missing  bool   `json:"missing,omitempty"`
+}
+
+type graphEdgeJSON struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Optional bool   `json:"optional,omitempty"`
+	All      bool   `json:"all,omitempty"`
+	Alias    bool   `json:"alias,omitempty"`
+	Lazy     bool   `json:"lazy,omitempty"`
+	Func     bool   `json:"func,omitempty"`
+}
+
+func writeGraphJSON(w io.Writer, snap internal.GraphSnapshot) error {
+	out := graphJSON{
+		Nodes: make([]graphNodeJSON, len(snap.Nodes)),
+		Edges: make([]graphEdgeJSON, len(snap.Edges)),
+	}
+	for i, n := range snap.Nodes {
+		node := graphNodeJSON{
+			ID:      fmt.Sprintf("n%d", i),
+			Name:    n.Name,
+			Type:    n.Key.Type.String(),
+			Key:     n.Key.Name,
+			Alias:   n.Alias,
+			Missing: n.Missing,
+		}
+		if !n.Missing {
+			node.Lifetime = n.Lifetime.String()
+		}
+		out.Nodes[i] = node
+	}
+	for i, e := range snap.Edges {
+		out.Edges[i] = graphEdgeJSON{
+			From:     fmt.Sprintf("n%d", e.From),
+			To:       fmt.Sprintf("n%d", e.To),
+			Optional: e.Optional,
+			All:      e.All,
+			Alias:    e.Alias,
+			Lazy:     e.Lazy,
+			Func:     e.Func,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
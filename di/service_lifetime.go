@@ -1,16 +1,33 @@
 package di
 
 // ServiceLifetime specifies the lifetime of a service in the dependency injection container.
-// In this simplified DI container, only Singleton lifetime is supported.
 type ServiceLifetime int
 
 const (
 	// Singleton specifies that a single instance of the service will be created
 	// and shared across the entire application lifetime.
 	Singleton ServiceLifetime = iota
+
+	// Scoped specifies that a single instance of the service will be created per scope
+	// (see IServiceProvider.CreateScope) and disposed when the scope ends.
+	Scoped
+
+	// Transient specifies that a new instance of the service will be created every time
+	// it is resolved. Transients resolved from a scope are disposed with the scope;
+	// transients resolved from the root provider are not tracked.
+	Transient
 )
 
 // String returns the string representation of the ServiceLifetime.
 func (l ServiceLifetime) String() string {
-	return "Singleton"
+	switch l {
+	case Singleton:
+		return "Singleton"
+	case Scoped:
+		return "Scoped"
+	case Transient:
+		return "Transient"
+	default:
+		return "Unknown"
+	}
 }
@@ -1,9 +1,11 @@
 package di
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync/atomic"
 
 	"github.com/gocrud/csgo/di/internal"
@@ -26,14 +28,30 @@ type IServiceProvider interface {
 	resolveNamed(t reflect.Type, name string) (interface{}, error)
 	resolveAll(t reflect.Type) []interface{}
 
+	// CreateScope 创建新的服务作用域，用于解析 Scoped 服务。
+	// 从作用域的提供者再次调用 CreateScope 会创建一个独立的新作用域。
+	CreateScope() IServiceScope
+
 	// Dispose 释放所有资源。
 	Dispose() error
+
+	// DisposeAsync 按创建顺序的逆序释放所有资源，优先调用 IAsyncDisposable.DisposeAsync。
+	// ctx 到期后停止释放，返回的错误包含 *DisposeTimeoutError，列出未完成释放的服务。
+	DisposeAsync(ctx context.Context) error
+
+	// CreateChild 创建子容器，configure 中的注册覆盖当前容器的同名注册，
+	// 其余服务回退到当前容器解析（共享其单例）。释放子容器只释放子容器自己创建的实例。
+	CreateChild(configure func(services IServiceCollection)) IServiceProvider
 }
 
 // serviceProvider 是 IServiceProvider 的具体实现。
+// scope 为 nil 时表示根提供者，否则表示作用域提供者。
 type serviceProvider struct {
-	engine   *internal.Engine
-	disposed atomic.Bool
+	engine     *internal.Engine
+	scope      *internal.Scope
+	options    ServiceProviderOptions // 构建选项，子容器沿用
+	conditions ConditionContext       // 条件注册上下文，子容器沿用
+	disposed   atomic.Bool
 }
 
 // Get 检索服务并将其填充到目标指针中。
@@ -43,33 +61,7 @@ func (p *serviceProvider) Get(target interface{}) {
 		panic("service provider is disposed")
 	}
 
-	val := reflect.ValueOf(target)
-	if val.Kind() != reflect.Ptr || val.IsNil() {
-		panic("target must be a non-nil pointer")
-	}
-
-	elem := val.Elem()
-	elemType := elem.Type()
-
-	// 尝试 1：直接查找目标类型
-	service, err := p.engine.Resolve(elemType, "")
-	if err == nil {
-		elem.Set(reflect.ValueOf(service))
-		return
-	}
-
-	// 尝试 2：如果目标是值类型（结构体），尝试查找指针类型并自动解引用
-	if elemType.Kind() == reflect.Struct {
-		ptrType := reflect.PointerTo(elemType)
-		ptrService, ptrErr := p.engine.Resolve(ptrType, "")
-		if ptrErr == nil {
-			// 自动解引用：赋值值的副本
-			elem.Set(reflect.ValueOf(ptrService).Elem())
-			return
-		}
-	}
-
-	panic(fmt.Sprintf("service %v not found", elemType))
+	fillTarget(target, "", p.resolve)
 }
 
 // GetNamed 检索命名服务并将其填充到目标指针中。
@@ -78,6 +70,12 @@ func (p *serviceProvider) GetNamed(target interface{}, serviceKey string) {
 		panic("service provider is disposed")
 	}
 
+	fillTarget(target, serviceKey, p.resolve)
+}
+
+// fillTarget 解析服务并将其填充到目标指针中（如果未找到则 panic）。
+// 目标是值类型（结构体）时，也会查找指针类型并自动解引用。
+func fillTarget(target interface{}, name string, resolve func(reflect.Type, string) (interface{}, error)) {
 	val := reflect.ValueOf(target)
 	if val.Kind() != reflect.Ptr || val.IsNil() {
 		panic("target must be a non-nil pointer")
@@ -86,24 +84,36 @@ func (p *serviceProvider) GetNamed(target interface{}, serviceKey string) {
 	elem := val.Elem()
 	elemType := elem.Type()
 
-	// 尝试 1：直接查找
-	service, err := p.engine.Resolve(elemType, serviceKey)
+	// 尝试 1：直接查找目标类型
+	service, err := resolve(elemType, name)
 	if err == nil {
 		elem.Set(reflect.ValueOf(service))
 		return
 	}
 
-	// 尝试 2：对于值类型的自动解引用
+	// 尝试 2：如果目标是值类型（结构体），尝试查找指针类型并自动解引用
 	if elemType.Kind() == reflect.Struct {
 		ptrType := reflect.PointerTo(elemType)
-		ptrService, ptrErr := p.engine.Resolve(ptrType, serviceKey)
+		ptrService, ptrErr := resolve(ptrType, name)
 		if ptrErr == nil {
+			// 自动解引用：赋值值的副本
 			elem.Set(reflect.ValueOf(ptrService).Elem())
 			return
 		}
 	}
 
-	panic(fmt.Sprintf("named service %v[%s] not found", elemType, serviceKey))
+	if name == "" {
+		panic(fmt.Sprintf("service %v not found", elemType))
+	}
+	panic(fmt.Sprintf("named service %s not found", formatServiceName(elemType, name)))
+}
+
+// formatServiceName 返回服务的显示名称，命名服务附带键名。
+func formatServiceName(t reflect.Type, name string) string {
+	if name == "" {
+		return t.String()
+	}
+	return fmt.Sprintf("%v[%s]", t, name)
 }
 
 // resolveType 按类型解析服务（泛型 API 的内部方法）。
@@ -111,7 +121,7 @@ func (p *serviceProvider) resolveType(t reflect.Type) (interface{}, error) {
 	if p.disposed.Load() {
 		return nil, errors.New("provider disposed")
 	}
-	return p.engine.Resolve(t, "")
+	return p.resolve(t, "")
 }
 
 // resolveNamed 按类型解析命名服务（泛型 API 的内部方法）。
@@ -119,7 +129,7 @@ func (p *serviceProvider) resolveNamed(t reflect.Type, name string) (interface{}
 	if p.disposed.Load() {
 		return nil, errors.New("provider disposed")
 	}
-	return p.engine.Resolve(t, name)
+	return p.resolve(t, name)
 }
 
 // resolveAll 解析特定类型的所有服务（泛型 API 的内部方法）。
@@ -127,33 +137,199 @@ func (p *serviceProvider) resolveAll(t reflect.Type) []interface{} {
 	if p.disposed.Load() {
 		return nil
 	}
+	if p.scope != nil {
+		services, _ := p.scope.ResolveAll(t)
+		return services
+	}
 	services, _ := p.engine.ResolveAll(t)
 	return services
 }
 
-// Dispose 释放所有资源，包括单例服务。
-// 所有实现 IDisposable 的单例服务都将调用其 Dispose 方法。
+// resolve 根据提供者类型（根或作用域）解析服务。
+func (p *serviceProvider) resolve(t reflect.Type, name string) (interface{}, error) {
+	if p.scope != nil {
+		return p.scope.Resolve(t, name)
+	}
+	return p.engine.Resolve(t, name)
+}
+
+// CreateScope 创建新的服务作用域。
+// 作用域总是从根容器派生，作用域之间互不共享 Scoped 实例。
+func (p *serviceProvider) CreateScope() IServiceScope {
+	if p.disposed.Load() {
+		panic("service provider is disposed")
+	}
+
+	scope, err := p.engine.NewScope()
+	if err != nil {
+		panic(fmt.Sprintf("failed to create scope: %v", err))
+	}
+
+	return &serviceScope{
+		provider: &serviceProvider{
+			engine:     p.engine,
+			scope:      scope,
+			options:    p.options,
+			conditions: p.conditions,
+		},
+	}
+}
+
+// CreateChild 创建子容器，用于多租户或插件隔离。
+// 子容器使用与当前容器相同的构建选项和条件注册上下文：
+//   - configure 中的注册覆盖父容器的同名注册（[]T 分组同样整体覆盖）
+//   - 其余服务回退到父容器，按父容器的注册解析，共享父容器的单例
+//   - 释放子容器只释放子容器自己创建的实例，父容器不受影响
+//
+// 从作用域提供者调用时，子容器的父容器是根容器。
+//
+// 用法：
+//
+//	tenant := provider.CreateChild(func(services di.IServiceCollection) {
+//	    services.AddInstance(&TenantOptions{Name: "acme"})
+//	    services.Add(NewConnectionPool)
+//	})
+//	defer tenant.Dispose()
+func (p *serviceProvider) CreateChild(configure func(services IServiceCollection)) IServiceProvider {
+	if p.disposed.Load() {
+		panic("service provider is disposed")
+	}
+
+	child := &serviceCollection{
+		engine:     internal.NewChildEngine(p.engine),
+		conditions: p.conditions,
+	}
+	if configure != nil {
+		configure(child)
+	}
+	return child.BuildWithOptions(p.options)
+}
+
+// Dispose 释放所有资源，等价于 DisposeAsync(context.Background())。
+// 根提供者释放所有已创建的单例服务；
+// 作用域提供者只释放该作用域内创建的 Scoped 和 Transient 服务。
+// 两者都按创建顺序的逆序（LIFO）进行。
 func (p *serviceProvider) Dispose() error {
+	return p.DisposeAsync(context.Background())
+}
+
+// DisposeAsync 按创建顺序的逆序释放所有资源。
+// 实现 IAsyncDisposable 的服务调用 DisposeAsync(ctx)，否则调用 IDisposable.Dispose。
+// ctx 到期后停止释放，尚未完成释放的服务通过 *DisposeTimeoutError 报告。
+func (p *serviceProvider) DisposeAsync(ctx context.Context) error {
 	if !p.disposed.CompareAndSwap(false, true) {
 		return nil // Already disposed
 	}
 
-	// 释放所有实现 IDisposable 的单例服务
+	if p.scope != nil {
+		return disposeAll(ctx, p.scope.Close(), "scope")
+	}
+
+	// GetSingletons 按创建顺序返回，逆序后释放
 	singletons := p.engine.GetSingletons()
-	var errors []error
+	for i, j := 0, len(singletons)-1; i < j; i, j = i+1, j-1 {
+		singletons[i], singletons[j] = singletons[j], singletons[i]
+	}
+	return disposeAll(ctx, singletons, "provider")
+}
+
+// DisposeTimeoutError 表示在 ctx 到期前未能完成释放的服务。
+type DisposeTimeoutError struct {
+	// Services 未完成释放的服务类型，按释放顺序排列。
+	// 第一个是超时时正在释放的服务，其余是因超时而未释放的服务。
+	Services []string
+	// Err 是 ctx.Err()，通常为 context.DeadlineExceeded。
+	Err error
+}
 
-	// 按相反顺序释放（LIFO）
-	for i := len(singletons) - 1; i >= 0; i-- {
-		if disposable, ok := singletons[i].(IDisposable); ok {
-			if err := disposable.Dispose(); err != nil {
-				errors = append(errors, fmt.Errorf("failed to dispose singleton at index %d: %w", i, err))
+func (e *DisposeTimeoutError) Error() string {
+	return fmt.Sprintf("disposal timed out (%v), %d service(s) not disposed: %s",
+		e.Err, len(e.Services), strings.Join(e.Services, ", "))
+}
+
+// Unwrap 支持 errors.Is(err, context.DeadlineExceeded)。
+func (e *DisposeTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// disposeAll 按给定顺序释放实例并汇总错误，ctx 到期后停止。
+// 同一实例（例如多次 AddInstance 的同一对象，或直接返回 inner 的装饰器）只释放一次，
+// 与 InitializeSingletons 的去重方式一致。
+func disposeAll(ctx context.Context, instances []interface{}, owner string) error {
+	var errs []error
+	var timedOut []string
+	seen := make(map[interface{}]bool)
+	for i, instance := range instances {
+		if !isDisposable(instance) {
+			continue
+		}
+		if reflect.ValueOf(instance).Comparable() {
+			if seen[instance] {
+				continue
 			}
+			seen[instance] = true
+		}
+		if len(timedOut) > 0 {
+			timedOut = append(timedOut, fmt.Sprintf("%T", instance))
+			continue
+		}
+
+		completed, err := disposeInstance(ctx, instance)
+		if !completed {
+			timedOut = append(timedOut, fmt.Sprintf("%T", instance))
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to dispose %T (#%d): %w", instance, i, err))
 		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("provider disposal encountered %d error(s): %v", len(errors), errors)
+	if len(timedOut) > 0 {
+		errs = append(errs, &DisposeTimeoutError{Services: timedOut, Err: ctx.Err()})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s disposal encountered %d error(s): %w", owner, len(errs), errors.Join(errs...))
 	}
 
 	return nil
 }
+
+// isDisposable 判断实例是否需要释放。
+func isDisposable(instance interface{}) bool {
+	switch instance.(type) {
+	case IAsyncDisposable, IDisposable:
+		return true
+	default:
+		return false
+	}
+}
+
+// disposeInstance 释放单个实例；ctx 在释放完成前到期时返回 completed = false。
+// 优先使用 IAsyncDisposable，超时后释放操作仍在后台继续，但不再等待。
+func disposeInstance(ctx context.Context, instance interface{}) (completed bool, err error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	var dispose func() error
+	switch d := instance.(type) {
+	case IAsyncDisposable:
+		dispose = func() error { return d.DisposeAsync(ctx) }
+	case IDisposable:
+		dispose = d.Dispose
+	}
+
+	// 没有截止时间的 ctx 永远不会到期，直接同步释放
+	if ctx.Done() == nil {
+		return true, dispose()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- dispose() }()
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+		return false, nil
+	}
+}
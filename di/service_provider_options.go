@@ -0,0 +1,26 @@
+package di
+
+// ServiceProviderOptions 控制服务提供者的构建方式。
+// 对应 .NET 的 ServiceProviderOptions。
+type ServiceProviderOptions struct {
+	// ValidateOnBuild 在构建时完整校验依赖图：所有依赖均可解析、没有循环依赖、
+	// 没有生命周期违规（单例依赖 Scoped/Transient）。
+	// 所有问题会一次性报告，而不是在第一个错误处停止。
+	ValidateOnBuild bool
+
+	// LazySingletons 构建时不实例化单例，改为在首次解析时创建。
+	// 与 ValidateOnBuild 一起使用时，可以在不调用任何构造函数的情况下验证容器配置：
+	//
+	//	di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{
+	//	    ValidateOnBuild: true,
+	//	    LazySingletons:  true,
+	//	})
+	LazySingletons bool
+
+	// ParallelSingletons 构建时并发创建单例的最大 worker 数，0 或 1 表示串行创建。
+	// 互不依赖的单例并发创建，每个单例仍在其所有依赖创建完成之后才开始，
+	// 适用于有大量执行慢 I/O（加载证书、预热缓存）的单例的应用。
+	// 构造函数出错时返回的错误与串行模式相同（按依赖顺序最先失败的单例）。
+	// 启用后构造函数会在多个 goroutine 中执行，必须是并发安全的。
+	ParallelSingletons int
+}
@@ -0,0 +1,43 @@
+package di
+
+import "context"
+
+// IServiceScope 表示一个服务作用域。
+// 作用域内的 Scoped 服务只创建一次，并在 Dispose 时按创建顺序的逆序（LIFO）释放。
+// 对应 .NET 的 IServiceScope。
+//
+// 用法：
+//
+//	scope := provider.CreateScope()
+//	defer scope.Dispose()
+//	uow := di.Get[*UnitOfWork](scope.ServiceProvider())
+type IServiceScope interface {
+	// ServiceProvider 返回用于在该作用域内解析服务的提供者。
+	ServiceProvider() IServiceProvider
+
+	// Dispose 结束作用域并释放其中所有实现 IDisposable 的 Scoped 服务。
+	Dispose() error
+
+	// DisposeAsync 结束作用域，在 ctx 到期前释放其中的服务（支持 IAsyncDisposable）。
+	DisposeAsync(ctx context.Context) error
+}
+
+// serviceScope 是 IServiceScope 的具体实现。
+type serviceScope struct {
+	provider *serviceProvider
+}
+
+// ServiceProvider 返回作用域的服务提供者。
+func (s *serviceScope) ServiceProvider() IServiceProvider {
+	return s.provider
+}
+
+// Dispose 结束作用域。
+func (s *serviceScope) Dispose() error {
+	return s.provider.Dispose()
+}
+
+// DisposeAsync 结束作用域，ctx 到期后停止释放。
+func (s *serviceScope) DisposeAsync(ctx context.Context) error {
+	return s.provider.DisposeAsync(ctx)
+}
@@ -0,0 +1,90 @@
+package di
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StartupProfile 服务提供者构建和服务构造的耗时统计。
+type StartupProfile struct {
+	// BuildDuration 构建服务提供者的耗时（包括提前创建所有单例）。
+	BuildDuration time.Duration
+	// Constructors 所有被调用过的构造函数，按累计耗时从高到低排序。
+	Constructors []ConstructorTiming
+}
+
+// ConstructorTiming 单个构造函数的调用统计。
+// 耗时只包含构造函数本身，不包含其依赖的构造。
+type ConstructorTiming struct {
+	Service  string // 服务键的显示名称，命名服务附带键名
+	Lifetime ServiceLifetime
+	Calls    int64
+	Total    time.Duration
+	Max      time.Duration
+	// AllocatedBytes 单例构造期间的堆分配字节数（近似值，并发构造时包含其他 goroutine 的分配）。
+	// Scoped 和 Transient 服务不统计。
+	AllocatedBytes uint64
+}
+
+// StartupReport 返回服务提供者的构造耗时统计，用于定位冷启动缓慢的服务。
+// 只有 BuildServiceProvider 构建的提供者记录耗时；其他提供者返回空报告。
+//
+//	report := di.StartupReport(provider)
+//	for _, c := range report.Slowest(5) {
+//	    fmt.Printf("%8v  %s\n", c.Total, c.Service)
+//	}
+func StartupReport(provider IServiceProvider) *StartupProfile {
+	sp, ok := provider.(*serviceProvider)
+	if !ok {
+		return &StartupProfile{}
+	}
+
+	stats := sp.engine.ConstructorStats()
+	report := &StartupProfile{
+		BuildDuration: sp.engine.CompileDuration(),
+		Constructors:  make([]ConstructorTiming, len(stats)),
+	}
+	for i, s := range stats {
+		report.Constructors[i] = ConstructorTiming{
+			Service:        s.Name,
+			Lifetime:       ServiceLifetime(s.Lifetime),
+			Calls:          s.Calls,
+			Total:          s.Total,
+			Max:            s.Max,
+			AllocatedBytes: s.AllocatedBytes,
+		}
+	}
+	return report
+}
+
+// Slowest 返回累计耗时最长的 n 个构造函数。
+func (r *StartupProfile) Slowest(n int) []ConstructorTiming {
+	if n > len(r.Constructors) {
+		n = len(r.Constructors)
+	}
+	return r.Constructors[:n]
+}
+
+// String 以表格形式返回报告，列出所有构造函数。
+func (r *StartupProfile) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "service provider built in %v\n", r.BuildDuration.Round(time.Microsecond))
+	for _, c := range r.Constructors {
+		fmt.Fprintf(&b, "%12v  %6d call(s)  %10s  %-9s  %s\n",
+			c.Total.Round(time.Microsecond), c.Calls, formatBytes(c.AllocatedBytes), c.Lifetime, c.Service)
+	}
+	return b.String()
+}
+
+// formatBytes 以 B/KB/MB 显示字节数
+func formatBytes(n uint64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,66 @@
+package di_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type ReportSlowCert struct{}
+type ReportFastConfig struct{}
+type ReportRequest struct{}
+
+// TestStartupReportRanksSlowestConstructor tests that the slowest constructor is reported first
+func TestStartupReportRanksSlowestConstructor(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.Add(func() *ReportFastConfig { return &ReportFastConfig{} })
+	services.Add(func(_ *ReportFastConfig) *ReportSlowCert {
+		time.Sleep(20 * time.Millisecond)
+		return &ReportSlowCert{}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	report := di.StartupReport(provider)
+	if report.BuildDuration < 20*time.Millisecond {
+		t.Errorf("expected build duration to include the slow constructor, got %v", report.BuildDuration)
+	}
+
+	slowest := report.Slowest(1)
+	if len(slowest) != 1 || !strings.Contains(slowest[0].Service, "ReportSlowCert") {
+		t.Fatalf("expected ReportSlowCert to be the slowest constructor, got %+v", slowest)
+	}
+	if slowest[0].Total < 20*time.Millisecond || slowest[0].Calls != 1 {
+		t.Errorf("expected one call of at least 20ms, got %d call(s) in %v", slowest[0].Calls, slowest[0].Total)
+	}
+	if slowest[0].Lifetime != di.Singleton {
+		t.Errorf("expected Singleton lifetime, got %v", slowest[0].Lifetime)
+	}
+
+	if len(report.Slowest(10)) != 2 {
+		t.Errorf("expected Slowest to be capped at the number of constructors, got %d", len(report.Slowest(10)))
+	}
+	if !strings.Contains(report.String(), "ReportFastConfig") {
+		t.Errorf("expected the report text to list every constructor, got:\n%s", report.String())
+	}
+}
+
+// TestStartupReportCountsTransientCalls tests that every transient construction is counted
+func TestStartupReportCountsTransientCalls(t *testing.T) {
+	services := di.NewServiceCollection()
+	services.AddTransient(func() *ReportRequest { return &ReportRequest{} })
+	provider := di.BuildServiceProvider(services)
+
+	for i := 0; i < 3; i++ {
+		di.Get[*ReportRequest](provider)
+	}
+
+	constructors := di.StartupReport(provider).Constructors
+	if len(constructors) != 1 || constructors[0].Calls != 3 {
+		t.Fatalf("expected 3 calls of the transient constructor, got %+v", constructors)
+	}
+	if constructors[0].AllocatedBytes != 0 {
+		t.Errorf("expected no allocation tracking for transients, got %d", constructors[0].AllocatedBytes)
+	}
+}
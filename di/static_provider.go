@@ -0,0 +1,286 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// StaticService 描述生成容器中的一个服务绑定。
+// 由 csgo-di 生成（见 cmd/csgo-di），通常不需要手写。
+type StaticService struct {
+	// Type 服务类型；通过 as= 暴露的接口各自对应一个绑定
+	Type reflect.Type
+	// Lifetime 服务生命周期
+	Lifetime ServiceLifetime
+	// Resolve 在作用域中解析服务，内部直接调用构造函数
+	Resolve func(scope *StaticScope) (any, error)
+}
+
+// StaticProvider 由 csgo-di 生成的容器使用的 IServiceProvider 实现。
+// 服务通过生成的代码直接调用构造函数创建，不使用 reflect.Value.Call；
+// di.Get[T] 等 API 仍按类型查找，生成的类型化访问方法则完全不需要反射。
+type StaticProvider struct {
+	services []StaticService
+	byType   map[reflect.Type][]int // 服务类型到绑定下标（按注册顺序）
+	scope    *StaticScope
+	disposed atomic.Bool
+}
+
+// NewStaticProvider 创建生成容器的服务提供者，并按注册顺序提前创建所有单例。
+// slots 为实例缓存槽的数量，每个构造函数占用一个槽。
+// 创建单例失败时 panic，与 BuildServiceProvider 一致。
+func NewStaticProvider(slots int, services []StaticService) *StaticProvider {
+	p := &StaticProvider{
+		services: services,
+		byType:   make(map[reflect.Type][]int),
+		scope:    newStaticScope(nil, slots),
+	}
+	for i, s := range services {
+		p.byType[s.Type] = append(p.byType[s.Type], i)
+	}
+
+	for _, s := range services {
+		if s.Lifetime != Singleton {
+			continue
+		}
+		if _, err := s.Resolve(p.scope); err != nil {
+			panic(fmt.Sprintf("failed to build service provider: failed to create singleton %v: %v", s.Type, err))
+		}
+	}
+	return p
+}
+
+// Scope 返回提供者的实例缓存，供生成的类型化访问方法使用。
+func (p *StaticProvider) Scope() *StaticScope {
+	return p.scope
+}
+
+// Get 检索服务并将其填充到目标指针中（如果未找到则 panic）。
+func (p *StaticProvider) Get(target interface{}) {
+	if p.disposed.Load() {
+		panic("service provider is disposed")
+	}
+	fillTarget(target, "", p.resolve)
+}
+
+// GetNamed 检索命名服务并将其填充到目标指针中。
+// 生成容器不支持命名服务，因此总是 panic。
+func (p *StaticProvider) GetNamed(target interface{}, serviceKey string) {
+	if p.disposed.Load() {
+		panic("service provider is disposed")
+	}
+	fillTarget(target, serviceKey, p.resolve)
+}
+
+// resolveType 按类型解析服务（泛型 API 的内部方法）。
+func (p *StaticProvider) resolveType(t reflect.Type) (interface{}, error) {
+	if p.disposed.Load() {
+		return nil, errors.New("provider disposed")
+	}
+	return p.resolve(t, "")
+}
+
+// resolveNamed 按类型解析命名服务（泛型 API 的内部方法）。
+func (p *StaticProvider) resolveNamed(t reflect.Type, name string) (interface{}, error) {
+	if p.disposed.Load() {
+		return nil, errors.New("provider disposed")
+	}
+	return p.resolve(t, name)
+}
+
+// resolveAll 按注册顺序解析类型的所有服务（泛型 API 的内部方法）。
+func (p *StaticProvider) resolveAll(t reflect.Type) []interface{} {
+	if p.disposed.Load() {
+		return nil
+	}
+
+	var result []interface{}
+	for _, i := range p.byType[t] {
+		instance, err := p.services[i].Resolve(p.scope)
+		if err != nil {
+			continue
+		}
+		result = append(result, instance)
+	}
+	return result
+}
+
+// resolve 解析类型最后注册的服务；生成容器不支持命名服务。
+func (p *StaticProvider) resolve(t reflect.Type, name string) (interface{}, error) {
+	indexes := p.byType[t]
+	if name != "" || len(indexes) == 0 {
+		return nil, fmt.Errorf("service %s not registered", formatServiceName(t, name))
+	}
+	return p.services[indexes[len(indexes)-1]].Resolve(p.scope)
+}
+
+// CreateScope 创建新的服务作用域。
+// 作用域总是从根提供者派生，作用域之间互不共享 Scoped 实例。
+func (p *StaticProvider) CreateScope() IServiceScope {
+	if p.disposed.Load() {
+		panic("service provider is disposed")
+	}
+
+	root := p.scope.rootScope()
+	return &staticServiceScope{
+		provider: &StaticProvider{
+			services: p.services,
+			byType:   p.byType,
+			scope:    newStaticScope(root, len(root.slots)),
+		},
+	}
+}
+
+// Dispose 释放所有资源，等价于 DisposeAsync(context.Background())。
+func (p *StaticProvider) Dispose() error {
+	return p.DisposeAsync(context.Background())
+}
+
+// DisposeAsync 按创建顺序的逆序释放所有资源。
+// 根提供者释放所有已创建的单例；作用域提供者只释放该作用域内创建的 Scoped 和 Transient 服务。
+func (p *StaticProvider) DisposeAsync(ctx context.Context) error {
+	if !p.disposed.CompareAndSwap(false, true) {
+		return nil // Already disposed
+	}
+
+	owner := "provider"
+	if p.scope.root != nil {
+		owner = "scope"
+	}
+
+	instances := p.scope.close()
+	for i, j := 0, len(instances)-1; i < j; i, j = i+1, j-1 {
+		instances[i], instances[j] = instances[j], instances[i]
+	}
+	return disposeAll(ctx, instances, owner)
+}
+
+// CreateChild 生成容器不支持子容器，总是 panic。
+// 需要子容器时，使用 di.NewServiceCollection 构建的提供者。
+func (p *StaticProvider) CreateChild(configure func(services IServiceCollection)) IServiceProvider {
+	panic("child containers are not supported by generated providers")
+}
+
+// staticServiceScope 是生成容器的 IServiceScope 实现。
+type staticServiceScope struct {
+	provider *StaticProvider
+}
+
+func (s *staticServiceScope) ServiceProvider() IServiceProvider {
+	return s.provider
+}
+
+func (s *staticServiceScope) Dispose() error {
+	return s.provider.Dispose()
+}
+
+func (s *staticServiceScope) DisposeAsync(ctx context.Context) error {
+	return s.provider.DisposeAsync(ctx)
+}
+
+// StaticScope 生成容器的实例缓存。
+// 根作用域缓存 Singleton，子作用域缓存 Scoped；两者都记录实例的创建完成顺序，用于逆序释放。
+type StaticScope struct {
+	root    *StaticScope // nil 表示根作用域
+	slots   []staticSlot // 以构造函数的槽号为下标
+	mu      sync.Mutex
+	created []any
+}
+
+// staticSlot 单个构造函数的实例缓存
+type staticSlot struct {
+	mu    sync.Mutex
+	done  bool
+	value any
+}
+
+func newStaticScope(root *StaticScope, slots int) *StaticScope {
+	return &StaticScope{root: root, slots: make([]staticSlot, slots)}
+}
+
+// rootScope 返回根作用域
+func (s *StaticScope) rootScope() *StaticScope {
+	if s.root != nil {
+		return s.root
+	}
+	return s
+}
+
+// track 记录创建完成的实例
+func (s *StaticScope) track(instance any) {
+	s.mu.Lock()
+	s.created = append(s.created, instance)
+	s.mu.Unlock()
+}
+
+// snapshot 返回按创建顺序排列的实例副本
+func (s *StaticScope) snapshot() []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.created)
+}
+
+// close 返回按创建顺序排列的实例，并清空记录
+func (s *StaticScope) close() []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := s.created
+	s.created = nil
+	return created
+}
+
+// StaticSingleton 返回根作用域中槽 slot 的单例，首次调用时通过 create 创建。
+// 创建失败时不缓存，下次调用会重试。
+func StaticSingleton[T any](scope *StaticScope, slot int, create func() (T, error)) (T, error) {
+	return cachedInstance(scope.rootScope(), slot, create)
+}
+
+// StaticScoped 返回当前作用域中槽 slot 的实例，首次调用时通过 create 创建。
+// Scoped 服务不能从根提供者解析。
+func StaticScoped[T any](scope *StaticScope, slot int, create func() (T, error)) (T, error) {
+	if scope.root == nil {
+		var zero T
+		return zero, fmt.Errorf("scoped service %v cannot be resolved from root provider", reflect.TypeFor[T]())
+	}
+	return cachedInstance(scope, slot, create)
+}
+
+// StaticTransient 每次调用 create 创建新实例；在作用域中创建的实例随作用域释放。
+func StaticTransient[T any](scope *StaticScope, create func() (T, error)) (T, error) {
+	instance, err := create()
+	if err == nil && scope.root != nil {
+		scope.track(instance)
+	}
+	return instance, err
+}
+
+// StaticMust 返回 instance，err 不为 nil 时 panic。供生成的类型化访问方法使用。
+func StaticMust[T any](instance T, err error) T {
+	if err != nil {
+		panic(fmt.Sprintf("failed to resolve service %v: %v", reflect.TypeFor[T](), err))
+	}
+	return instance
+}
+
+// cachedInstance 从作用域的槽中获取实例，不存在时创建（每个槽持有独立的锁，依赖可以在创建过程中递归解析）
+func cachedInstance[T any](scope *StaticScope, slot int, create func() (T, error)) (T, error) {
+	s := &scope.slots[slot]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return s.value.(T), nil
+	}
+	instance, err := create()
+	if err != nil {
+		return instance, err
+	}
+	s.value, s.done = instance, true
+	scope.track(instance)
+	return instance, nil
+}
@@ -0,0 +1,132 @@
+package di_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+type StaticDB struct{ log *[]string }
+type StaticRepo struct {
+	DB  *StaticDB
+	log *[]string
+}
+type StaticUnitOfWork struct {
+	Repo *StaticRepo
+	log  *[]string
+}
+type IStaticPlugin interface{ Name() string }
+
+func (d *StaticDB) Dispose() error         { *d.log = append(*d.log, "db"); return nil }
+func (r *StaticRepo) Dispose() error       { *r.log = append(*r.log, "repo"); return nil }
+func (u *StaticUnitOfWork) Dispose() error { *u.log = append(*u.log, "uow"); return nil }
+func (r *StaticRepo) Name() string         { return "repo" }
+
+// newStaticProvider builds a provider the way csgo-di generated code does
+func newStaticProvider(log *[]string) *di.StaticProvider {
+	db := func(s *di.StaticScope) (*StaticDB, error) {
+		return di.StaticSingleton(s, 0, func() (*StaticDB, error) { return &StaticDB{log: log}, nil })
+	}
+	repo := func(s *di.StaticScope) (*StaticRepo, error) {
+		return di.StaticSingleton(s, 1, func() (v *StaticRepo, err error) {
+			p0, err := db(s)
+			if err != nil {
+				return v, err
+			}
+			return &StaticRepo{DB: p0, log: log}, nil
+		})
+	}
+	uow := func(s *di.StaticScope) (*StaticUnitOfWork, error) {
+		return di.StaticScoped(s, 2, func() (v *StaticUnitOfWork, err error) {
+			p0, err := repo(s)
+			if err != nil {
+				return v, err
+			}
+			return &StaticUnitOfWork{Repo: p0, log: log}, nil
+		})
+	}
+
+	return di.NewStaticProvider(3, []di.StaticService{
+		// Registered dependent-first: construction order still follows dependencies
+		{Type: reflect.TypeFor[*StaticRepo](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return repo(s) }},
+		{Type: reflect.TypeFor[IStaticPlugin](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return repo(s) }},
+		{Type: reflect.TypeFor[*StaticDB](), Lifetime: di.Singleton, Resolve: func(s *di.StaticScope) (any, error) { return db(s) }},
+		{Type: reflect.TypeFor[*StaticUnitOfWork](), Lifetime: di.Scoped, Resolve: func(s *di.StaticScope) (any, error) { return uow(s) }},
+	})
+}
+
+// TestStaticProviderResolvesThroughGenericAPI tests that generated providers work with di.Get and friends
+func TestStaticProviderResolvesThroughGenericAPI(t *testing.T) {
+	var log []string
+	provider := newStaticProvider(&log)
+
+	repo := di.Get[*StaticRepo](provider)
+	if repo.DB != di.Get[*StaticDB](provider) {
+		t.Error("expected singletons to be shared")
+	}
+	if di.Get[IStaticPlugin](provider) != IStaticPlugin(repo) {
+		t.Error("expected the interface binding to share the singleton")
+	}
+	if _, ok := di.TryGet[*StaticUnitOfWork](provider); ok {
+		t.Error("expected scoped services to be unavailable from the root provider")
+	}
+
+	var db StaticDB
+	provider.Get(&db)
+	if db.log != &log {
+		t.Error("expected value targets to be auto-dereferenced")
+	}
+}
+
+// TestStaticProviderScopes tests scoped caching and disposal in reverse construction order
+func TestStaticProviderScopes(t *testing.T) {
+	var log []string
+	provider := newStaticProvider(&log)
+
+	scope := provider.CreateScope()
+	sp := scope.ServiceProvider()
+	uow := di.Get[*StaticUnitOfWork](sp)
+	if uow != di.Get[*StaticUnitOfWork](sp) {
+		t.Error("expected one scoped instance per scope")
+	}
+	other := provider.CreateScope()
+	if di.Get[*StaticUnitOfWork](other.ServiceProvider()) == uow {
+		t.Error("expected scopes not to share scoped instances")
+	}
+
+	if err := scope.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(log, ",") != "uow" {
+		t.Errorf("expected the scope to dispose only its scoped services, got %v", log)
+	}
+
+	log = nil
+	if err := provider.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(log, ",") != "repo,db" {
+		t.Errorf("expected singletons to be disposed in reverse construction order, got %v", log)
+	}
+}
+
+// TestStaticProviderSingletonError tests that singleton construction errors surface when the provider is built
+func TestStaticProviderSingletonError(t *testing.T) {
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "failed to build service provider") || !strings.Contains(msg, "db unavailable") {
+			t.Errorf("expected a build error, got %q", msg)
+		}
+	}()
+
+	di.NewStaticProvider(1, []di.StaticService{{
+		Type:     reflect.TypeFor[*StaticDB](),
+		Lifetime: di.Singleton,
+		Resolve: func(s *di.StaticScope) (any, error) {
+			return di.StaticSingleton(s, 0, func() (*StaticDB, error) { return nil, errors.New("db unavailable") })
+		},
+	}})
+}
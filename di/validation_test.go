@@ -0,0 +1,103 @@
+package di_test
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gocrud/csgo/di"
+)
+
+// TestValidateOnBuildReportsAllProblems tests that every problem is reported in one error
+func TestValidateOnBuildReportsAllProblems(t *testing.T) {
+	type Missing struct{}
+	type NeedsMissing struct{}
+	type CycleA struct{}
+	type CycleB struct{}
+	type RequestState struct{}
+	type Captive struct{}
+
+	var calls atomic.Int32
+	services := di.NewServiceCollection()
+	services.Add(func(m *Missing) *NeedsMissing { calls.Add(1); return &NeedsMissing{} })
+	services.Add(func(b *CycleB) *CycleA { calls.Add(1); return &CycleA{} })
+	services.Add(func(a *CycleA) *CycleB { calls.Add(1); return &CycleB{} })
+	services.AddScoped(func() *RequestState { calls.Add(1); return &RequestState{} })
+	services.Add(func(r *RequestState) *Captive { calls.Add(1); return &Captive{} })
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for invalid service graph")
+		}
+		errStr := r.(string)
+		for _, expected := range []string{
+			"3 error(s)",
+			"service '*github.com/gocrud/csgo/di_test.Missing' not found",
+			"circular dependency detected",
+			"captive dependency detected",
+		} {
+			if !strings.Contains(errStr, expected) {
+				t.Errorf("Expected error to contain %q:\n%s", expected, errStr)
+			}
+		}
+		if calls.Load() != 0 {
+			t.Errorf("Expected no constructor calls during validation, got %d", calls.Load())
+		}
+	}()
+
+	di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{ValidateOnBuild: true})
+}
+
+// TestValidateOnBuildWithoutInstantiation tests validating wiring without calling constructors
+func TestValidateOnBuildWithoutInstantiation(t *testing.T) {
+	var calls atomic.Int32
+	services := di.NewServiceCollection()
+	services.Add(func() *TestService { calls.Add(1); return &TestService{Value: "lazy"} })
+	services.Add(func(s *TestService) *UserRepo { calls.Add(1); return &UserRepo{Service: s} })
+
+	provider := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{
+		ValidateOnBuild: true,
+		LazySingletons:  true,
+	})
+	if calls.Load() != 0 {
+		t.Fatalf("Expected no constructor calls at build, got %d", calls.Load())
+	}
+
+	repo := di.Get[*UserRepo](provider)
+	if repo.Service != di.Get[*TestService](provider) {
+		t.Error("Expected lazily created singletons to be shared")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected each constructor to run once, got %d", calls.Load())
+	}
+}
+
+// TestLazySingletonConcurrentResolve tests that a lazy singleton is created once under concurrency
+func TestLazySingletonConcurrentResolve(t *testing.T) {
+	var calls atomic.Int32
+	services := di.NewServiceCollection()
+	services.Add(func() *TestService { calls.Add(1); return &TestService{} })
+	provider := di.BuildServiceProviderWithOptions(services, di.ServiceProviderOptions{LazySingletons: true})
+
+	var wg sync.WaitGroup
+	results := make([]*TestService, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = di.Get[*TestService](provider)
+		}(i)
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected one construction, got %d", calls.Load())
+	}
+	for _, r := range results {
+		if r != results[0] {
+			t.Fatal("Expected all goroutines to observe the same instance")
+		}
+	}
+}
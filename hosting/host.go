@@ -2,6 +2,7 @@ package hosting
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gocrud/csgo/di"
+	"github.com/gocrud/csgo/logging"
 )
 
 // IHost represents a configured application ready to run.
@@ -37,6 +39,19 @@ type Host struct {
 	lifetime        IHostApplicationLifetime
 	hostedServices  []IHostedService
 	shutdownTimeout time.Duration
+	options         HostOptions
+	loggerFactory   logging.ILoggerFactory
+	logger          logging.Logger
+
+	failMu  sync.Mutex
+	failure error // first background service failure that stopped the host
+}
+
+// backgroundTask is implemented by hosted services that run work in the background,
+// such as those embedding BackgroundService.
+type backgroundTask interface {
+	ExecuteTask() <-chan struct{}
+	ExecuteError() error
 }
 
 // NewHost creates a new Host instance.
@@ -46,29 +61,102 @@ func NewHost(services di.IServiceProvider, environment *Environment, lifetime IH
 
 // NewHostWithTimeout creates a new Host instance with custom shutdown timeout.
 func NewHostWithTimeout(services di.IServiceProvider, environment *Environment, lifetime IHostApplicationLifetime, hostedServices []IHostedService, shutdownTimeout time.Duration) *Host {
+	loggerFactory := resolveLoggerFactory(services)
 	return &Host{
 		services:        services,
 		environment:     environment,
 		lifetime:        lifetime,
 		hostedServices:  hostedServices,
 		shutdownTimeout: shutdownTimeout,
+		loggerFactory:   loggerFactory,
+		logger:          logging.GetLogger[Host](loggerFactory),
 	}
 }
 
+// resolveLoggerFactory returns the registered ILoggerFactory, or a console logger factory
+// when logging was not added to the services.
+func resolveLoggerFactory(services di.IServiceProvider) logging.ILoggerFactory {
+	if services != nil {
+		if factory, ok := di.TryGet[logging.ILoggerFactory](services); ok {
+			return factory
+		}
+	}
+	return logging.NewLoggerFactory(logging.NewConsoleProvider(logging.ConsoleOptions{}))
+}
+
 // Services returns the service provider.
 func (h *Host) Services() di.IServiceProvider {
 	return h.services
 }
 
-// Start starts the host.
+// Start starts the host: it initializes singletons, runs the StartingAsync callbacks,
+// starts the hosted services and runs the StartedAsync callbacks.
+// If any step fails, the startup is rolled back: lifecycle services whose StartingAsync
+// completed get StoppingAsync and StoppedAsync, and the hosted services that already
+// started are stopped, each in reverse order.
 func (h *Host) Start(ctx context.Context) error {
+	// The startup timeout bounds the whole startup sequence
+	if h.options.StartupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.options.StartupTimeout)
+		defer cancel()
+	}
+
 	// Notify starting
 	h.lifetime.NotifyStarting()
 
+	// Initialize singletons in dependency order before any hosted service starts
+	if err := di.InitializeSingletons(ctx, h.services); err != nil {
+		return err
+	}
+
+	// Let supervised services report restarts through the application lifetime and logging
+	for _, svc := range h.hostedServices {
+		if aware, ok := svc.(hostAware); ok {
+			aware.attachHost(h.lifetime, h.loggerFactory)
+		}
+	}
+
+	lifecycle := h.lifecycleServices()
+	concurrent := h.options.ServicesStartConcurrently
+	starting := make([]bool, len(lifecycle))
+	started := make([]bool, len(h.hostedServices))
+
+	// Run StartingAsync callbacks before any hosted service starts
+	errs := forEachService(len(lifecycle), concurrent, false, false, func(i int) error {
+		if err := lifecycle[i].StartingAsync(ctx); err != nil {
+			return err
+		}
+		starting[i] = true
+		return nil
+	})
+
 	// Start all hosted services
+	if len(errs) == 0 {
+		errs = forEachService(len(h.hostedServices), concurrent, false, false, func(i int) error {
+			if err := h.hostedServices[i].StartAsync(ctx); err != nil {
+				return err
+			}
+			started[i] = true
+			return nil
+		})
+	}
+
+	// Run StartedAsync callbacks once every hosted service has started
+	if len(errs) == 0 {
+		errs = forEachService(len(lifecycle), concurrent, false, false, func(i int) error {
+			return lifecycle[i].StartedAsync(ctx)
+		})
+	}
+
+	if len(errs) > 0 {
+		return h.rollback(lifecycle, starting, started, fmt.Errorf("failed to start hosted service: %w", errors.Join(errs...)))
+	}
+
+	// Watch background services only once startup succeeded, so a rollback is not reported as a failure
 	for _, svc := range h.hostedServices {
-		if err := svc.StartAsync(ctx); err != nil {
-			return fmt.Errorf("failed to start hosted service: %w", err)
+		if task, ok := svc.(backgroundTask); ok {
+			go h.watchBackgroundTask(task)
 		}
 	}
 
@@ -78,39 +166,169 @@ func (h *Host) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop stops the host.
-func (h *Host) Stop(ctx context.Context) error {
-	// Notify stopping
-	h.lifetime.NotifyStopping()
+// rollback undoes a failed startup in the same order as Stop: StoppingAsync for the lifecycle
+// services whose StartingAsync completed, StopAsync for the hosted services that started and
+// StoppedAsync for the same lifecycle services, each sequentially in reverse order.
+// It is bounded by the shutdown timeout, since the startup context may already be expired.
+func (h *Host) rollback(lifecycle []IHostedLifecycleService, starting, started []bool, err error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.shutdownTimeout)
+	defer cancel()
 
-	// Stop all hosted services in reverse order
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(h.hostedServices))
+	var errs []error
+	reverse := func(done []bool, fn func(i int) error) {
+		for i := len(done) - 1; i >= 0; i-- {
+			if !done[i] {
+				continue
+			}
+			if stopErr := fn(i); stopErr != nil {
+				errs = append(errs, stopErr)
+			}
+		}
+	}
 
-	for i := len(h.hostedServices) - 1; i >= 0; i-- {
-		wg.Add(1)
-		go func(svc IHostedService) {
-			defer wg.Done()
-			if err := svc.StopAsync(ctx); err != nil {
-				errChan <- err
+	reverse(starting, func(i int) error { return lifecycle[i].StoppingAsync(ctx) })
+	reverse(started, func(i int) error { return h.hostedServices[i].StopAsync(ctx) })
+	reverse(starting, func(i int) error { return lifecycle[i].StoppedAsync(ctx) })
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w (errors during rollback: %v)", err, errs)
+	}
+	return err
+}
+
+// forEachService calls fn for the indexes 0..n-1, in reverse order if reverse is set.
+// Sequential calls stop at the first error unless continueOnError is set, which the stop side
+// uses so that one failing service does not keep the others running; concurrent calls all run.
+// Every error is returned.
+func forEachService(n int, concurrent, reverse, continueOnError bool, fn func(i int) error) []error {
+	index := func(i int) int {
+		if reverse {
+			return n - 1 - i
+		}
+		return i
+	}
+
+	if !concurrent {
+		var errs []error
+		for i := 0; i < n; i++ {
+			if err := fn(index(i)); err != nil {
+				errs = append(errs, err)
+				if !continueOnError {
+					break
+				}
 			}
-		}(h.hostedServices[i])
+		}
+		return errs
 	}
 
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(index(i))
+	}
 	wg.Wait()
-	close(errChan)
 
-	// Collect errors
-	var errors []error
-	for err := range errChan {
-		errors = append(errors, err)
+	var result []error
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// watchBackgroundTask waits for a background service to finish and applies the
+// configured BackgroundServiceExceptionBehavior if it failed while the host was running.
+func (h *Host) watchBackgroundTask(task backgroundTask) {
+	done := task.ExecuteTask()
+	if done == nil {
+		return
+	}
+	<-done
+
+	err := task.ExecuteError()
+	if err == nil || errors.Is(err, context.Canceled) || h.isStopping() {
+		return
+	}
+
+	h.logger.LogError(err, "Background service failed")
+	if h.options.BackgroundServiceExceptionBehavior != BackgroundServiceStopHost {
+		return
 	}
 
+	h.failMu.Lock()
+	if h.failure == nil {
+		h.failure = err
+	}
+	h.failMu.Unlock()
+
+	h.logger.LogCritical(nil, "Stopping host because a background service failed")
+	h.lifetime.StopApplication()
+}
+
+// isStopping reports whether the host has begun stopping.
+func (h *Host) isStopping() bool {
+	select {
+	case <-h.lifetime.ApplicationStopping():
+		return true
+	default:
+		return false
+	}
+}
+
+// lifecycleServices returns the hosted services implementing IHostedLifecycleService in start order.
+func (h *Host) lifecycleServices() []IHostedLifecycleService {
+	var services []IHostedLifecycleService
+	for _, svc := range h.hostedServices {
+		if lifecycle, ok := svc.(IHostedLifecycleService); ok {
+			services = append(services, lifecycle)
+		}
+	}
+	return services
+}
+
+// Stop stops the host: it runs the StoppingAsync callbacks, stops all hosted services,
+// runs the StoppedAsync callbacks and then disposes the service provider.
+// Services are stopped in reverse order, or concurrently if ServicesStopConcurrently is set.
+// A failing service does not prevent the remaining services from stopping; every error is returned.
+// The context bounds every step; RunWithContext uses the configured shutdown timeout.
+func (h *Host) Stop(ctx context.Context) error {
+	// Notify stopping
+	h.lifetime.NotifyStopping()
+
+	lifecycle := h.lifecycleServices()
+	concurrent := h.options.ServicesStopConcurrently
+	var errs []error
+
+	// Run StoppingAsync callbacks before any hosted service stops
+	errs = append(errs, forEachService(len(lifecycle), concurrent, true, true, func(i int) error {
+		return lifecycle[i].StoppingAsync(ctx)
+	})...)
+
+	// Stop all hosted services
+	errs = append(errs, forEachService(len(h.hostedServices), concurrent, true, true, func(i int) error {
+		return h.hostedServices[i].StopAsync(ctx)
+	})...)
+
+	// Run StoppedAsync callbacks once every hosted service has stopped
+	errs = append(errs, forEachService(len(lifecycle), concurrent, true, true, func(i int) error {
+		return lifecycle[i].StoppedAsync(ctx)
+	})...)
+
 	// Notify stopped
 	h.lifetime.NotifyStopped()
 
-	if len(errors) > 0 {
-		return fmt.Errorf("errors during shutdown: %v", errors)
+	// Dispose services in reverse construction order, bounded by the same context
+	if err := h.services.DisposeAsync(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("errors during shutdown: %v", errs)
 	}
 
 	return nil
@@ -134,16 +352,26 @@ func (h *Host) RunWithContext(ctx context.Context) error {
 
 	select {
 	case sig := <-quit:
-		fmt.Printf("\nReceived signal: %s\n", sig)
+		h.logger.LogInformation("Received signal: %s", sig)
 	case <-ctx.Done():
-		fmt.Println("\nContext cancelled")
+		h.logger.LogInformation("Context cancelled")
 	case <-h.lifetime.ApplicationStopping():
-		fmt.Println("\nApplication stopping requested")
+		h.logger.LogInformation("Application stopping requested")
 	}
 
 	// Stop the host with configured timeout
 	stopCtx, cancel := context.WithTimeout(context.Background(), h.shutdownTimeout)
 	defer cancel()
 
-	return h.Stop(stopCtx)
+	if err := h.Stop(stopCtx); err != nil {
+		return err
+	}
+
+	// A failed background service that stopped the host is reported to the caller
+	h.failMu.Lock()
+	defer h.failMu.Unlock()
+	if h.failure != nil {
+		return fmt.Errorf("background service failed: %w", h.failure)
+	}
+	return nil
 }
@@ -1,11 +1,13 @@
 package hosting
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/gocrud/csgo/config"
 	"github.com/gocrud/csgo/di"
+	"github.com/gocrud/csgo/logging"
 )
 
 // IHostBuilder provides a mechanism for configuring and creating a host.
@@ -23,7 +25,10 @@ type HostBuilder struct {
 	Services             di.IServiceCollection
 	Configuration        config.IConfigurationManager
 	Environment          *Environment
+	Logging              *logging.LoggingBuilder
 	configurationActions []func(config.IConfigurationBuilder)
+	hostOptionsActions   []func(*HostOptions)
+	modules              moduleRegistry
 }
 
 // CreateDefaultBuilder creates a host builder with default configuration.
@@ -50,10 +55,17 @@ func CreateDefaultBuilder(args ...string) *HostBuilder {
 	services.Add(func() *Environment { return env })
 	services.Add(func() IHostApplicationLifetime { return NewApplicationLifetime() })
 
+	// Conditional registrations are evaluated against the final environment and configuration
+	di.SetConditionContext(services, &hostConditionContext{env: env, config: configManager})
+
+	// Console logging, plus a rolling file when Logging:File:Path is configured
+	loggingBuilder := logging.AddLogging(services).AddConsole().AddFile()
+
 	return &HostBuilder{
 		Services:             services,
 		Configuration:        configManager,
 		Environment:          env,
+		Logging:              loggingBuilder,
 		configurationActions: make([]func(config.IConfigurationBuilder), 0),
 	}
 }
@@ -63,10 +75,12 @@ func CreateDefaultBuilder(args ...string) *HostBuilder {
 func CreateEmptyBuilder() *HostBuilder {
 	env := NewEnvironment()
 	services := di.NewServiceCollection()
+	di.SetConditionContext(services, &hostConditionContext{env: env})
 
 	return &HostBuilder{
 		Services:    services,
 		Environment: env,
+		Logging:     logging.AddLogging(services).AddConsole(),
 	}
 }
 
@@ -95,8 +109,29 @@ func (b *HostBuilder) ConfigureHostConfiguration(configure func(config config.IC
 	return b
 }
 
+// ConfigureHostOptions adds a delegate for configuring the HostOptions of the built host.
+// Corresponds to .NET IHostBuilder.ConfigureHostOptions().
+func (b *HostBuilder) ConfigureHostOptions(configure func(options *HostOptions)) *HostBuilder {
+	b.hostOptionsActions = append(b.hostOptionsActions, configure)
+	return b
+}
+
 // Build builds the host.
+// Panics if TryBuild returns an error.
 func (b *HostBuilder) Build() IHost {
+	host, err := b.TryBuild()
+	if err != nil {
+		panic(fmt.Sprintf("failed to build host: %v", err))
+	}
+	return host
+}
+
+// TryBuild builds the host, or returns the errors recorded by AddModule.
+func (b *HostBuilder) TryBuild() (IHost, error) {
+	if err := b.modules.err(); err != nil {
+		return nil, err
+	}
+
 	// Build service provider
 	provider := di.BuildServiceProvider(b.Services)
 
@@ -111,13 +146,18 @@ func (b *HostBuilder) Build() IHost {
 
 	// Create host
 	host := NewHostWithTimeout(provider, b.Environment, lifetime, hostedServices, shutdownTimeout)
+	host.options.StartupTimeout = b.getStartupTimeout()
+	for _, configure := range b.hostOptionsActions {
+		configure(&host.options)
+	}
 
-	return host
+	return host, nil
 }
 
-// resolveHostedServices resolves all registered hosted services using new API
+// resolveHostedServices resolves all registered hosted services in registration order,
+// which is the order the host starts them in.
 func (b *HostBuilder) resolveHostedServices(provider di.IServiceProvider) []IHostedService {
-	// Use GetAll generic API
+	// GetAll returns services in registration order
 	services := di.GetAll[IHostedService](provider)
 	return services
 }
@@ -140,3 +180,35 @@ func (b *HostBuilder) getShutdownTimeout() time.Duration {
 
 	return 30 * time.Second
 }
+
+// getStartupTimeout gets the startup timeout from configuration (in seconds).
+// Returns 0 (no limit) when not configured.
+func (b *HostBuilder) getStartupTimeout() time.Duration {
+	if b.Configuration == nil {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(b.Configuration.Get("server.startupTimeout")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return 0
+}
+
+// hostConditionContext exposes the host environment and configuration
+// to conditional service registrations (di.AddIf and friends).
+type hostConditionContext struct {
+	env    IHostEnvironment
+	config config.IConfiguration // nil for empty builders
+}
+
+func (c *hostConditionContext) EnvironmentName() string {
+	return c.env.Name()
+}
+
+func (c *hostConditionContext) GetConfig(key string) string {
+	if c.config == nil {
+		return ""
+	}
+	return c.config.Get(key)
+}
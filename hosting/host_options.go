@@ -0,0 +1,46 @@
+package hosting
+
+import "time"
+
+// BackgroundServiceExceptionBehavior specifies what the host does when a
+// BackgroundService execute function returns an error or panics.
+type BackgroundServiceExceptionBehavior int
+
+const (
+	// BackgroundServiceStopHost reports the error and stops the host (default).
+	BackgroundServiceStopHost BackgroundServiceExceptionBehavior = iota
+	// BackgroundServiceIgnore reports the error and keeps the host running.
+	BackgroundServiceIgnore
+)
+
+// String returns the behavior name.
+func (b BackgroundServiceExceptionBehavior) String() string {
+	switch b {
+	case BackgroundServiceStopHost:
+		return "StopHost"
+	case BackgroundServiceIgnore:
+		return "Ignore"
+	default:
+		return "Unknown"
+	}
+}
+
+// HostOptions configures the behavior of the Host.
+// Corresponds to .NET HostOptions.
+type HostOptions struct {
+	// ServicesStartConcurrently starts hosted services (and runs their StartingAsync and
+	// StartedAsync callbacks) concurrently instead of sequentially in registration order.
+	ServicesStartConcurrently bool
+
+	// ServicesStopConcurrently stops hosted services concurrently instead of
+	// sequentially in reverse registration order.
+	ServicesStopConcurrently bool
+
+	// StartupTimeout bounds Host.Start, including singleton initialization.
+	// Defaults to the "server.startupTimeout" configuration value (seconds); 0 means no limit.
+	StartupTimeout time.Duration
+
+	// BackgroundServiceExceptionBehavior determines what happens when a background
+	// service fails. Errors caused by the host stopping the service are not failures.
+	BackgroundServiceExceptionBehavior BackgroundServiceExceptionBehavior
+}
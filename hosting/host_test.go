@@ -0,0 +1,388 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+)
+
+// eventLog records lifecycle calls from several services.
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.events, ", ")
+}
+
+// recordingService is a hosted service that records its calls and can fail a chosen step.
+type recordingService struct {
+	name   string
+	log    *eventLog
+	failOn string
+}
+
+func (s *recordingService) record(step string) error {
+	s.log.add(step + " " + s.name)
+	if step == s.failOn {
+		return &stepError{step: step, service: s.name}
+	}
+	return nil
+}
+
+func (s *recordingService) StartAsync(ctx context.Context) error { return s.record("Start") }
+func (s *recordingService) StopAsync(ctx context.Context) error  { return s.record("Stop") }
+
+type stepError struct{ step, service string }
+
+func (e *stepError) Error() string { return e.step + " " + e.service + " failed" }
+
+// recordingLifecycleService also implements IHostedLifecycleService.
+type recordingLifecycleService struct {
+	recordingService
+}
+
+func newLifecycleService(name string, log *eventLog) *recordingLifecycleService {
+	return &recordingLifecycleService{recordingService{name: name, log: log}}
+}
+
+func (s *recordingLifecycleService) StartingAsync(ctx context.Context) error {
+	return s.record("Starting")
+}
+
+func (s *recordingLifecycleService) StartedAsync(ctx context.Context) error {
+	return s.record("Started")
+}
+
+func (s *recordingLifecycleService) StoppingAsync(ctx context.Context) error {
+	return s.record("Stopping")
+}
+
+func (s *recordingLifecycleService) StoppedAsync(ctx context.Context) error {
+	return s.record("Stopped")
+}
+
+func TestHostLifecycleCallbackOrder(t *testing.T) {
+	log := &eventLog{}
+	host := newTestHost(nil,
+		newLifecycleService("a", log),
+		&recordingService{name: "plain", log: log},
+		newLifecycleService("b", log),
+	)
+
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	want := "Starting a, Starting b, Start a, Start plain, Start b, Started a, Started b"
+	if got := log.String(); got != want {
+		t.Errorf("Unexpected start order:\n got: %s\nwant: %s", got, want)
+	}
+
+	log.events = nil
+	if err := host.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	want = "Stopping b, Stopping a, Stop b, Stop plain, Stop a, Stopped b, Stopped a"
+	if got := log.String(); got != want {
+		t.Errorf("Unexpected stop order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestHostStopContinuesAfterStopError(t *testing.T) {
+	log := &eventLog{}
+	host := newTestHost(nil,
+		&recordingService{name: "a", log: log},
+		&recordingService{name: "b", log: log, failOn: "Stop"},
+		&recordingService{name: "c", log: log},
+	)
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	log.events = nil
+	err := host.Stop(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Stop b failed") {
+		t.Fatalf("Expected the stop error to be returned, got %v", err)
+	}
+	if got := log.String(); got != "Stop c, Stop b, Stop a" {
+		t.Errorf("Expected every service to be stopped, got %s", got)
+	}
+}
+
+func TestHostStopContinuesAfterCallbackErrors(t *testing.T) {
+	log := &eventLog{}
+	stoppingFails, stoppedFails := newLifecycleService("b", log), newLifecycleService("c", log)
+	host := newTestHost(nil, newLifecycleService("a", log), stoppingFails, stoppedFails, newLifecycleService("d", log))
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	log.events = nil
+	stoppingFails.failOn = "Stopping"
+	stoppedFails.failOn = "Stopped"
+	err := host.Stop(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Stopping b failed") || !strings.Contains(err.Error(), "Stopped c failed") {
+		t.Fatalf("Expected both callback errors, got %v", err)
+	}
+	want := "Stopping d, Stopping c, Stopping b, Stopping a, Stop d, Stop c, Stop b, Stop a, " +
+		"Stopped d, Stopped c, Stopped b, Stopped a"
+	if got := log.String(); got != want {
+		t.Errorf("Expected every callback to run:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// startingObserver records the state of the lifetime events when its callbacks run.
+type startingObserver struct {
+	recordingLifecycleService
+	lifetime IHostApplicationLifetime
+}
+
+func (s *startingObserver) StartingAsync(ctx context.Context) error {
+	s.log.add("Starting: starting=" + closed(s.lifetime.ApplicationStarting()) + " started=" + closed(s.lifetime.ApplicationStarted()))
+	return nil
+}
+
+func (s *startingObserver) StartedAsync(ctx context.Context) error {
+	s.log.add("Started: started=" + closed(s.lifetime.ApplicationStarted()))
+	return nil
+}
+
+func closed(ch <-chan struct{}) string {
+	select {
+	case <-ch:
+		return "closed"
+	default:
+		return "open"
+	}
+}
+
+func TestApplicationStartingEvent(t *testing.T) {
+	log := &eventLog{}
+	observer := &startingObserver{recordingLifecycleService: *newLifecycleService("observer", log)}
+	host := newTestHost(nil, observer)
+	observer.lifetime = host.lifetime
+
+	if closed(host.lifetime.ApplicationStarting()) != "open" {
+		t.Fatal("Expected ApplicationStarting to be open before Start")
+	}
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// ApplicationStarting fires before any hosted service callback; ApplicationStarted after all of them
+	want := "Starting: starting=closed started=open, Start observer, Started: started=open"
+	if got := log.String(); got != want {
+		t.Errorf("Unexpected event states:\n got: %s\nwant: %s", got, want)
+	}
+	if closed(host.lifetime.ApplicationStarted()) != "closed" {
+		t.Error("Expected ApplicationStarted to be closed after Start")
+	}
+
+	// NotifyStarting is idempotent
+	host.lifetime.NotifyStarting()
+}
+
+func TestHostRollbackOnStartFailure(t *testing.T) {
+	log := &eventLog{}
+	failing := newLifecycleService("b", log)
+	failing.failOn = "Start"
+	host := newTestHost(nil,
+		newLifecycleService("a", log),
+		&recordingService{name: "plain", log: log},
+		failing,
+		newLifecycleService("c", log),
+	)
+
+	err := host.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Start b failed") {
+		t.Fatalf("Expected the start failure, got %v", err)
+	}
+
+	// c never started; b failed to start but its StartingAsync completed
+	want := "Starting a, Starting b, Starting c, Start a, Start plain, Start b, " +
+		"Stopping c, Stopping b, Stopping a, Stop plain, Stop a, Stopped c, Stopped b, Stopped a"
+	if got := log.String(); got != want {
+		t.Errorf("Unexpected rollback order:\n got: %s\nwant: %s", got, want)
+	}
+	if closed(host.lifetime.ApplicationStarted()) != "open" {
+		t.Error("Expected ApplicationStarted not to fire after a failed start")
+	}
+}
+
+func TestHostRollbackOnStartingFailure(t *testing.T) {
+	log := &eventLog{}
+	failing := newLifecycleService("b", log)
+	failing.failOn = "Starting"
+	host := newTestHost(nil, newLifecycleService("a", log), failing)
+
+	if err := host.Start(context.Background()); err == nil {
+		t.Fatal("Expected Start to fail")
+	}
+
+	want := "Starting a, Starting b, Stopping a, Stopped a"
+	if got := log.String(); got != want {
+		t.Errorf("Unexpected rollback order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestHostRollbackReportsStopErrors(t *testing.T) {
+	log := &eventLog{}
+	host := newTestHost(nil,
+		&recordingService{name: "a", log: log, failOn: "Stop"},
+		&recordingService{name: "b", log: log, failOn: "Start"},
+	)
+
+	err := host.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Start b failed") || !strings.Contains(err.Error(), "errors during rollback: [Stop a failed]") {
+		t.Fatalf("Expected the start and rollback errors, got %v", err)
+	}
+	var stepErr *stepError
+	if !errors.As(err, &stepErr) || stepErr.service != "b" {
+		t.Errorf("Expected the start error to be wrapped, got %v", err)
+	}
+}
+
+// barrierService blocks in StartAsync and StopAsync until all services of its barrier have entered.
+type barrierService struct {
+	barrier *barrier
+	fail    bool
+}
+
+type barrier struct {
+	n       int32
+	entered atomic.Int32
+	ready   chan struct{}
+}
+
+func newBarrier(n int) *barrier {
+	return &barrier{n: int32(n), ready: make(chan struct{})}
+}
+
+func (b *barrier) wait() error {
+	if b.entered.Add(1) == b.n {
+		close(b.ready)
+	}
+	select {
+	case <-b.ready:
+		return nil
+	case <-time.After(time.Second):
+		return errors.New("services did not run concurrently")
+	}
+}
+
+func (s *barrierService) StartAsync(ctx context.Context) error {
+	if err := s.barrier.wait(); err != nil {
+		return err
+	}
+	if s.fail {
+		return errors.New("start failed")
+	}
+	return nil
+}
+
+func (s *barrierService) StopAsync(ctx context.Context) error {
+	return s.barrier.wait()
+}
+
+func TestHostServicesStartAndStopConcurrently(t *testing.T) {
+	start, stop := newBarrier(3), newBarrier(3)
+	services := []IHostedService{
+		&barrierService{barrier: start}, &barrierService{barrier: start}, &barrierService{barrier: start},
+	}
+	host := newTestHost(func(o *HostOptions) {
+		o.ServicesStartConcurrently = true
+		o.ServicesStopConcurrently = true
+	}, services...)
+
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for _, svc := range services {
+		svc.(*barrierService).barrier = stop
+	}
+	if err := host.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
+
+func TestHostConcurrentStartReportsAllErrors(t *testing.T) {
+	log := &eventLog{}
+	host := newTestHost(func(o *HostOptions) { o.ServicesStartConcurrently = true },
+		&recordingService{name: "a", log: log, failOn: "Start"},
+		&recordingService{name: "b", log: log, failOn: "Start"},
+		&recordingService{name: "c", log: log},
+	)
+
+	err := host.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Start a failed") || !strings.Contains(err.Error(), "Start b failed") {
+		t.Fatalf("Expected every start error, got %v", err)
+	}
+	// Only c started, so only c is rolled back
+	if !strings.HasSuffix(log.String(), "Stop c") || strings.Count(log.String(), "Stop") != 1 {
+		t.Errorf("Expected only the started service to be stopped, got %s", log.String())
+	}
+}
+
+func TestHostStartupTimeout(t *testing.T) {
+	log := &eventLog{}
+	slow := &blockingService{}
+	host := newTestHost(func(o *HostOptions) { o.StartupTimeout = 20 * time.Millisecond },
+		&recordingService{name: "a", log: log},
+		slow,
+	)
+
+	begin := time.Now()
+	err := host.Start(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the startup timeout, got %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Expected Start to return at the startup timeout, took %v", elapsed)
+	}
+	// The rollback runs with the shutdown timeout, not the expired startup context
+	if got := log.String(); got != "Start a, Stop a" {
+		t.Errorf("Expected the started service to be rolled back, got %s", got)
+	}
+}
+
+// blockingService starts only when its context is done.
+type blockingService struct{}
+
+func (s *blockingService) StartAsync(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *blockingService) StopAsync(ctx context.Context) error { return nil }
+
+func TestHostStartWithStaticProvider(t *testing.T) {
+	provider := di.NewStaticProvider(0, nil)
+	log := &eventLog{}
+	host := NewHostWithTimeout(provider, NewEnvironment(), NewApplicationLifetime(),
+		[]IHostedService{&recordingService{name: "a", log: log}}, 5*time.Second)
+
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Expected a generated container to be supported, got %v", err)
+	}
+	if err := host.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if got := log.String(); got != "Start a, Stop a" {
+		t.Errorf("Unexpected calls: %s", got)
+	}
+}
@@ -3,6 +3,7 @@ package hosting
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
 // IHostedService defines methods for objects that are managed by the host.
@@ -14,17 +15,56 @@ type IHostedService interface {
 	StopAsync(ctx context.Context) error
 }
 
+// IHostedLifecycleService is an IHostedService with additional callbacks that the host
+// invokes around StartAsync and StopAsync of all hosted services.
+// Corresponds to .NET IHostedLifecycleService.
+type IHostedLifecycleService interface {
+	IHostedService
+
+	// StartingAsync is called before StartAsync of any hosted service.
+	StartingAsync(ctx context.Context) error
+
+	// StartedAsync is called after StartAsync of all hosted services has completed.
+	StartedAsync(ctx context.Context) error
+
+	// StoppingAsync is called before StopAsync of any hosted service,
+	// e.g. to deregister from a load balancer while requests are still served.
+	StoppingAsync(ctx context.Context) error
+
+	// StoppedAsync is called after StopAsync of all hosted services has completed,
+	// e.g. to flush telemetry.
+	StoppedAsync(ctx context.Context) error
+}
+
 // BackgroundService is a base class for implementing a long running IHostedService.
+//
+// The execute function runs on its own goroutine with a stopping context that is
+// cancelled when StopAsync is called. StopAsync then waits for the function to return
+// (or for the stop context to expire), so workers can finish in-flight work on shutdown.
 type BackgroundService struct {
-	stoppingChan chan struct{}
-	executeFunc  func(context.Context) error
+	executeFunc func(context.Context) error
+
+	once     sync.Once
+	stopping context.Context
+	stop     context.CancelFunc
+
+	mu   sync.Mutex
+	done chan struct{} // closed when the execute function returns; nil until started
+	err  error
 }
 
 // NewBackgroundService creates a new BackgroundService.
 func NewBackgroundService() *BackgroundService {
-	return &BackgroundService{
-		stoppingChan: make(chan struct{}),
-	}
+	s := &BackgroundService{}
+	s.init()
+	return s
+}
+
+// init creates the stopping context; it also makes the zero value usable.
+func (s *BackgroundService) init() {
+	s.once.Do(func() {
+		s.stopping, s.stop = context.WithCancel(context.Background())
+	})
 }
 
 // SetExecuteFunc sets the execution function for the background service.
@@ -33,30 +73,84 @@ func (s *BackgroundService) SetExecuteFunc(fn func(context.Context) error) {
 	s.executeFunc = fn
 }
 
-// StartAsync starts the background service.
+// StartAsync starts the execute function on a new goroutine and returns immediately.
+// The execute function receives the stopping context, not ctx, which only bounds startup.
 func (s *BackgroundService) StartAsync(ctx context.Context) error {
+	s.init()
 	if s.executeFunc == nil {
 		return nil
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return nil // Already started
+	}
+
+	done := make(chan struct{})
+	s.done = done
 	go func() {
-		if err := s.executeFunc(ctx); err != nil {
-			fmt.Printf("BackgroundService error: %v\n", err)
-		}
+		err := s.execute()
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		close(done)
 	}()
 
 	return nil
 }
 
-// StopAsync stops the background service.
+// execute runs the execute function, converting a panic into an error.
+func (s *BackgroundService) execute() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("background service panicked: %v", r)
+		}
+	}()
+	return s.executeFunc(s.stopping)
+}
+
+// StopAsync cancels the stopping context and waits until the execute function returns
+// or ctx expires, in which case ctx.Err() is returned.
 func (s *BackgroundService) StopAsync(ctx context.Context) error {
-	close(s.stoppingChan)
-	return nil
+	s.init()
+	s.stop()
+
+	done := s.ExecuteTask()
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background service did not stop in time: %w", ctx.Err())
+	}
 }
 
 // StoppingToken returns a channel that is closed when the service should stop.
 func (s *BackgroundService) StoppingToken() <-chan struct{} {
-	return s.stoppingChan
+	s.init()
+	return s.stopping.Done()
+}
+
+// ExecuteTask returns a channel that is closed when the execute function returns,
+// or nil if the service has not been started.
+func (s *BackgroundService) ExecuteTask() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done == nil {
+		return nil
+	}
+	return s.done
+}
+
+// ExecuteError returns the error returned by the execute function once it has completed.
+func (s *BackgroundService) ExecuteError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
 }
 
 // ExecuteAsync is the method that derived types should override to provide their execution logic.
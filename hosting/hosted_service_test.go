@@ -0,0 +1,208 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/di"
+	"github.com/gocrud/csgo/logging"
+)
+
+// newTestHost creates a host for the hosted services with framework logging discarded.
+func newTestHost(configure func(options *HostOptions), hostedServices ...IHostedService) *Host {
+	services := di.NewServiceCollection()
+	logging.AddLogging(services)
+	host := NewHostWithTimeout(di.BuildServiceProvider(services), NewEnvironment(), NewApplicationLifetime(), hostedServices, 5*time.Second)
+	if configure != nil {
+		configure(&host.options)
+	}
+	return host
+}
+
+// waitClosed fails the test if ch is not closed within a second.
+func waitClosed(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for %s", what)
+	}
+}
+
+func TestBackgroundServiceStopCancelsStoppingContext(t *testing.T) {
+	svc := NewBackgroundService()
+	running := make(chan struct{})
+	var ctxErr atomic.Value
+	svc.SetExecuteFunc(func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		ctxErr.Store(ctx.Err())
+		return ctx.Err()
+	})
+
+	if err := svc.StartAsync(context.Background()); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	waitClosed(t, running, "execute to start")
+
+	select {
+	case <-svc.StoppingToken():
+		t.Fatal("Expected the stopping token to stay open before StopAsync")
+	default:
+	}
+
+	if err := svc.StopAsync(context.Background()); err != nil {
+		t.Fatalf("StopAsync failed: %v", err)
+	}
+	waitClosed(t, svc.StoppingToken(), "the stopping token")
+	if ctxErr.Load() != context.Canceled {
+		t.Errorf("Expected execute to observe context.Canceled, got %v", ctxErr.Load())
+	}
+	if !errors.Is(svc.ExecuteError(), context.Canceled) {
+		t.Errorf("Expected ExecuteError to return the execute result, got %v", svc.ExecuteError())
+	}
+}
+
+func TestBackgroundServiceStopWaitsForExecute(t *testing.T) {
+	svc := NewBackgroundService()
+	var finished atomic.Bool
+	svc.SetExecuteFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond) // finish in-flight work
+		finished.Store(true)
+		return nil
+	})
+	svc.StartAsync(context.Background())
+
+	if err := svc.StopAsync(context.Background()); err != nil {
+		t.Fatalf("StopAsync failed: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Expected StopAsync to wait for execute to return")
+	}
+	waitClosed(t, svc.ExecuteTask(), "the execute task")
+}
+
+func TestBackgroundServiceStopReturnsWhenContextExpires(t *testing.T) {
+	svc := NewBackgroundService()
+	release := make(chan struct{})
+	defer close(release)
+	svc.SetExecuteFunc(func(ctx context.Context) error {
+		<-release // ignores the stopping context
+		return nil
+	})
+	svc.StartAsync(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := svc.StopAsync(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected StopAsync to return when the context expires, took %v", elapsed)
+	}
+}
+
+func TestBackgroundServiceNotStarted(t *testing.T) {
+	svc := NewBackgroundService()
+	if svc.ExecuteTask() != nil {
+		t.Error("Expected no execute task before StartAsync")
+	}
+	if err := svc.StopAsync(context.Background()); err != nil {
+		t.Errorf("Expected StopAsync to succeed before StartAsync, got %v", err)
+	}
+}
+
+// runHost runs the host until it stops by itself or ctx is cancelled.
+func runHost(t *testing.T, host *Host, ctx context.Context) <-chan error {
+	t.Helper()
+	result := make(chan error, 1)
+	go func() { result <- host.RunWithContext(ctx) }()
+	waitClosed(t, host.lifetime.ApplicationStarted(), "the host to start")
+	return result
+}
+
+func TestBackgroundServiceFailureStopsHost(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		work func(ctx context.Context) error
+		want string
+	}{
+		{"error", func(ctx context.Context) error { return errors.New("queue unavailable") }, "queue unavailable"},
+		{"panic", func(ctx context.Context) error { panic("nil consumer") }, "background service panicked: nil consumer"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewBackgroundService()
+			start := make(chan struct{})
+			svc.SetExecuteFunc(func(ctx context.Context) error {
+				<-start // fail only once the host is watching the service
+				return tc.work(ctx)
+			})
+			host := newTestHost(nil, svc)
+
+			result := runHost(t, host, context.Background())
+			close(start)
+
+			select {
+			case err := <-result:
+				if err == nil || !strings.Contains(err.Error(), "background service failed: "+tc.want) {
+					t.Errorf("Expected the failure to be returned, got %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Expected the host to stop after the background service failed")
+			}
+		})
+	}
+}
+
+func TestBackgroundServiceFailureIgnored(t *testing.T) {
+	svc := NewBackgroundService()
+	start := make(chan struct{})
+	svc.SetExecuteFunc(func(ctx context.Context) error {
+		<-start
+		return errors.New("queue unavailable")
+	})
+	host := newTestHost(func(o *HostOptions) {
+		o.BackgroundServiceExceptionBehavior = BackgroundServiceIgnore
+	}, svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := runHost(t, host, ctx)
+	close(start)
+	waitClosed(t, svc.ExecuteTask(), "the execute task")
+
+	select {
+	case <-host.lifetime.ApplicationStopping():
+		t.Fatal("Expected the host to keep running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-result; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
+
+func TestBackgroundServiceCancellationOnStopIsNotAFailure(t *testing.T) {
+	svc := NewBackgroundService()
+	svc.SetExecuteFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("interrupted while draining")
+	})
+	host := newTestHost(nil, svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := runHost(t, host, ctx)
+	cancel()
+
+	if err := <-result; err != nil {
+		t.Errorf("Expected an error returned while stopping to be ignored, got %v", err)
+	}
+}
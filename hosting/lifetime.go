@@ -1,7 +1,16 @@
 package hosting
 
+import (
+	"slices"
+	"sync"
+)
+
 // IHostApplicationLifetime provides notifications for application lifetime events.
 type IHostApplicationLifetime interface {
+	// ApplicationStarting returns a channel that is closed when the application begins starting,
+	// before any hosted service is started.
+	ApplicationStarting() <-chan struct{}
+
 	// ApplicationStarted returns a channel that is closed when the application has fully started.
 	ApplicationStarted() <-chan struct{}
 
@@ -21,12 +30,32 @@ type IHostApplicationLifetime interface {
 	NotifyStopped()
 }
 
-// ApplicationLifetime implements IHostApplicationLifetime.
+// IServiceRestartEvents is an optional interface of IHostApplicationLifetime implementations
+// that report restarts of all supervised hosted services. ApplicationLifetime implements it:
+//
+//	if events, ok := lifetime.(hosting.IServiceRestartEvents); ok {
+//	    events.OnServiceRestart(func(e hosting.ServiceRestartEvent) { ... })
+//	}
+//
+// To observe a single service, use SupervisedService.OnRestart.
+type IServiceRestartEvents interface {
+	// OnServiceRestart registers a callback that is invoked each time a supervised
+	// hosted service is restarted.
+	OnServiceRestart(callback func(event ServiceRestartEvent))
+
+	// NotifyServiceRestart invokes the registered callbacks.
+	NotifyServiceRestart(event ServiceRestartEvent)
+}
+
+// ApplicationLifetime implements IHostApplicationLifetime and IServiceRestartEvents.
 type ApplicationLifetime struct {
 	startingChan chan struct{}
 	startedChan  chan struct{}
 	stoppingChan chan struct{}
 	stoppedChan  chan struct{}
+
+	restartMu        sync.Mutex
+	restartCallbacks []func(ServiceRestartEvent)
 }
 
 // NewApplicationLifetime creates a new ApplicationLifetime.
@@ -39,6 +68,10 @@ func NewApplicationLifetime() IHostApplicationLifetime {
 	}
 }
 
+func (l *ApplicationLifetime) ApplicationStarting() <-chan struct{} {
+	return l.startingChan
+}
+
 func (l *ApplicationLifetime) ApplicationStarted() <-chan struct{} {
 	return l.startedChan
 }
@@ -61,7 +94,12 @@ func (l *ApplicationLifetime) StopApplication() {
 }
 
 func (l *ApplicationLifetime) NotifyStarting() {
-	// Can trigger events here if needed
+	select {
+	case <-l.startingChan:
+		// Already closed
+	default:
+		close(l.startingChan)
+	}
 }
 
 func (l *ApplicationLifetime) NotifyStarted() {
@@ -81,3 +119,18 @@ func (l *ApplicationLifetime) NotifyStopped() {
 	close(l.stoppedChan)
 }
 
+func (l *ApplicationLifetime) OnServiceRestart(callback func(event ServiceRestartEvent)) {
+	l.restartMu.Lock()
+	defer l.restartMu.Unlock()
+	l.restartCallbacks = append(l.restartCallbacks, callback)
+}
+
+func (l *ApplicationLifetime) NotifyServiceRestart(event ServiceRestartEvent) {
+	l.restartMu.Lock()
+	callbacks := slices.Clone(l.restartCallbacks)
+	l.restartMu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
@@ -0,0 +1,118 @@
+package hosting
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gocrud/csgo/config"
+	"github.com/gocrud/csgo/di"
+)
+
+// IServiceModule packages a reusable set of service registrations,
+// such as an auth, persistence or messaging package shared between applications.
+//
+//	type PersistenceModule struct{}
+//
+//	func (PersistenceModule) ConfigureServices(services di.IServiceCollection, cfg config.IConfiguration, env hosting.IHostEnvironment) {
+//	    services.Add(NewDB)
+//	    services.AddHostedService(NewMigrationRunner)
+//	}
+//
+//	builder.AddModule(PersistenceModule{})
+type IServiceModule interface {
+	// ConfigureServices registers the module's services, including hosted services.
+	ConfigureServices(services di.IServiceCollection, config config.IConfiguration, env IHostEnvironment)
+}
+
+// IModuleDependencies is implemented by modules that require other modules.
+// Dependencies are configured before the module itself.
+type IModuleDependencies interface {
+	DependsOn() []IServiceModule
+}
+
+// moduleRegistry tracks the modules added to a builder.
+// A module is identified by its dynamic type, so each module type is configured once
+// no matter how many modules depend on it. Adding a module whose type was already added
+// with different settings (compared with reflect.DeepEqual) is an error.
+type moduleRegistry struct {
+	modules []IServiceModule
+	added   map[reflect.Type]IServiceModule
+	errs    []error
+}
+
+// add configures the module after its dependencies, using configure for each module.
+// Errors are recorded and reported by err.
+func (r *moduleRegistry) add(module IServiceModule, configure func(IServiceModule)) {
+	if r.added == nil {
+		r.added = make(map[reflect.Type]IServiceModule)
+	}
+	if err := r.visit(module, nil, configure); err != nil {
+		r.errs = append(r.errs, fmt.Errorf("failed to add module: %w", err))
+	}
+}
+
+// err returns the errors recorded while adding modules.
+func (r *moduleRegistry) err() error {
+	return errors.Join(r.errs...)
+}
+
+func (r *moduleRegistry) visit(module IServiceModule, path []reflect.Type, configure func(IServiceModule)) error {
+	if module == nil {
+		return fmt.Errorf("module is nil")
+	}
+
+	moduleType := reflect.TypeOf(module)
+	if existing, ok := r.added[moduleType]; ok {
+		if !reflect.DeepEqual(existing, module) {
+			return fmt.Errorf("module %s was already added with different settings", moduleType)
+		}
+		return nil
+	}
+	for i, t := range path {
+		if t == moduleType {
+			return fmt.Errorf("module dependency cycle: %s", formatModulePath(append(path[i:], moduleType)))
+		}
+	}
+
+	if deps, ok := module.(IModuleDependencies); ok {
+		path = append(path, moduleType)
+		for _, dep := range deps.DependsOn() {
+			if err := r.visit(dep, path, configure); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.added[moduleType] = module
+	r.modules = append(r.modules, module)
+	configure(module)
+	return nil
+}
+
+func formatModulePath(path []reflect.Type) string {
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[i] = t.String()
+	}
+	return strings.Join(names, " -> ")
+}
+
+// AddModule configures the given modules and their dependencies.
+// Each module type is configured only once; dependencies are configured first.
+// A dependency cycle, or a module type added again with different settings,
+// is reported by TryBuild (Build panics).
+func (b *HostBuilder) AddModule(modules ...IServiceModule) *HostBuilder {
+	for _, module := range modules {
+		b.modules.add(module, func(m IServiceModule) {
+			m.ConfigureServices(b.Services, b.Configuration, b.Environment)
+		})
+	}
+	return b
+}
+
+// Modules returns the configured modules, dependencies before their dependents.
+func (b *HostBuilder) Modules() []IServiceModule {
+	return b.modules.modules
+}
@@ -0,0 +1,281 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gocrud/csgo/logging"
+)
+
+// RestartMode specifies when a supervised service is restarted.
+type RestartMode int
+
+const (
+	// RestartNever runs the work once; a failure is handled by the host's
+	// BackgroundServiceExceptionBehavior.
+	RestartNever RestartMode = iota
+	// RestartOnFailure restarts the work when it returns an error or panics.
+	RestartOnFailure
+	// RestartAlways restarts the work whenever it returns, even without an error.
+	RestartAlways
+)
+
+// String returns the mode name.
+func (m RestartMode) String() string {
+	switch m {
+	case RestartNever:
+		return "Never"
+	case RestartOnFailure:
+		return "OnFailure"
+	case RestartAlways:
+		return "Always"
+	default:
+		return "Unknown"
+	}
+}
+
+// RestartPolicy configures how a supervised service is restarted.
+// The delay before each restart doubles with every consecutive failure,
+// starting at InitialBackoff and capped at MaxBackoff.
+type RestartPolicy struct {
+	Mode RestartMode
+
+	// MaxAttempts is the number of consecutive failed runs that are restarted before
+	// the service gives up; 0 means unlimited.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first restart (default 1 second).
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between restarts (default 1 minute).
+	MaxBackoff time.Duration
+}
+
+// backoff returns the delay before restarting after the given number of consecutive failures.
+func (p RestartPolicy) backoff(failures int) time.Duration {
+	delay := p.InitialBackoff
+	if delay <= 0 {
+		delay = time.Second
+	}
+	limit := p.MaxBackoff
+	if limit <= 0 {
+		limit = time.Minute
+	}
+
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// HealthStatus represents the health of a hosted service.
+type HealthStatus int
+
+const (
+	// HealthStatusHealthy means the service is running or completed normally.
+	HealthStatusHealthy HealthStatus = iota
+	// HealthStatusDegraded means the service failed and is waiting to be restarted.
+	HealthStatusDegraded
+	// HealthStatusUnhealthy means the service failed and will not be restarted.
+	HealthStatusUnhealthy
+)
+
+// String returns the status name.
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthStatusHealthy:
+		return "Healthy"
+	case HealthStatusDegraded:
+		return "Degraded"
+	case HealthStatusUnhealthy:
+		return "Unhealthy"
+	default:
+		return "Unknown"
+	}
+}
+
+// ServiceHealth is a snapshot of the health of a supervised service.
+type ServiceHealth struct {
+	Status    HealthStatus
+	Restarts  int   // total number of restarts
+	LastError error // error of the most recent failed run, if any
+}
+
+// ServiceRestartEvent describes a restart of a supervised service.
+type ServiceRestartEvent struct {
+	Service string        // name of the supervised service
+	Attempt int           // restart number, starting at 1
+	Delay   time.Duration // backoff before the restart
+	Err     error         // error of the failed run; nil for RestartAlways after a normal return
+}
+
+// SupervisedService is a BackgroundService that restarts its work according to a RestartPolicy.
+// Restarts are reported through OnRestart, Health and, when the application lifetime
+// implements it, IServiceRestartEvents.
+//
+// Example:
+//
+//	services.AddHostedService(func(c *QueueConsumer) hosting.IHostedService {
+//	    return hosting.NewSupervisedService("queue-consumer", hosting.RestartPolicy{
+//	        Mode:        hosting.RestartOnFailure,
+//	        MaxAttempts: 5,
+//	    }, c.Consume)
+//	})
+type SupervisedService struct {
+	*BackgroundService
+	name     string
+	policy   RestartPolicy
+	work     func(context.Context) error
+	lifetime IHostApplicationLifetime // set by the host before start
+	logger   logging.Logger           // set by the host before start
+
+	mu        sync.Mutex
+	health    ServiceHealth
+	callbacks []func(ServiceRestartEvent)
+}
+
+// hostAware is implemented by hosted services that report events through the host's
+// application lifetime and logging.
+type hostAware interface {
+	attachHost(lifetime IHostApplicationLifetime, loggerFactory logging.ILoggerFactory)
+}
+
+// NewSupervisedService creates a supervised background service that runs work under policy.
+func NewSupervisedService(name string, policy RestartPolicy, work func(ctx context.Context) error) *SupervisedService {
+	s := &SupervisedService{
+		BackgroundService: NewBackgroundService(),
+		name:              name,
+		policy:            policy,
+		work:              work,
+	}
+	s.SetExecuteFunc(s.supervise)
+	return s
+}
+
+// Name returns the service name used in restart events.
+func (s *SupervisedService) Name() string {
+	return s.name
+}
+
+// Health returns the current health of the service.
+func (s *SupervisedService) Health() ServiceHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.health
+}
+
+// OnRestart registers a callback that is invoked each time the service is restarted.
+func (s *SupervisedService) OnRestart(callback func(event ServiceRestartEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+func (s *SupervisedService) attachHost(lifetime IHostApplicationLifetime, loggerFactory logging.ILoggerFactory) {
+	s.lifetime = lifetime
+	s.logger = logging.GetLogger[SupervisedService](loggerFactory).With("service", s.name)
+}
+
+// supervise runs the work and restarts it according to the policy until the service is stopped.
+func (s *SupervisedService) supervise(ctx context.Context) error {
+	failures := 0
+	for {
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil // Stopped by the host
+		}
+
+		if err != nil {
+			failures++
+		} else {
+			failures = 0
+		}
+
+		if !s.shouldRestart(err, failures) {
+			if err == nil {
+				return nil
+			}
+			if s.policy.Mode != RestartNever {
+				err = fmt.Errorf("supervised service %s gave up after %d restarts: %w", s.name, s.Health().Restarts, err)
+			}
+			s.setHealth(HealthStatusUnhealthy, err, 0)
+			return err
+		}
+
+		status := HealthStatusHealthy
+		if err != nil {
+			status = HealthStatusDegraded
+		}
+		delay := s.policy.backoff(failures)
+		attempt := s.setHealth(status, err, 1)
+		s.notifyRestart(ServiceRestartEvent{Service: s.name, Attempt: attempt, Delay: delay, Err: err})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		s.setHealth(HealthStatusHealthy, nil, 0)
+	}
+}
+
+// runOnce runs the work once, converting a panic into an error.
+func (s *SupervisedService) runOnce(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("supervised service %s panicked: %v", s.name, r)
+		}
+	}()
+	return s.work(ctx)
+}
+
+// shouldRestart reports whether the policy restarts the work after it returned err.
+func (s *SupervisedService) shouldRestart(err error, failures int) bool {
+	switch s.policy.Mode {
+	case RestartOnFailure:
+		if err == nil {
+			return false
+		}
+	case RestartAlways:
+	default:
+		return false
+	}
+	return s.policy.MaxAttempts <= 0 || failures <= s.policy.MaxAttempts
+}
+
+// setHealth updates the health status and returns the total number of restarts.
+// A non-nil err is recorded as the last error; restarts is added to the restart count.
+func (s *SupervisedService) setHealth(status HealthStatus, err error, restarts int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health.Status = status
+	if err != nil {
+		s.health.LastError = err
+	}
+	s.health.Restarts += restarts
+	return s.health.Restarts
+}
+
+// notifyRestart reports a restart through logging, the OnRestart callbacks and the application lifetime.
+func (s *SupervisedService) notifyRestart(event ServiceRestartEvent) {
+	if event.Err != nil && s.logger != nil {
+		s.logger.Log(logging.LevelWarning, event.Err, "Supervised service failed, restarting",
+			"attempt", event.Attempt, "delay", event.Delay)
+	}
+
+	s.mu.Lock()
+	callbacks := slices.Clone(s.callbacks)
+	s.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(event)
+	}
+
+	if events, ok := s.lifetime.(IServiceRestartEvents); ok {
+		events.NotifyServiceRestart(event)
+	}
+}
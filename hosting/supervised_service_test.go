@@ -0,0 +1,231 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	policy := RestartPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for failures, want := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		20: time.Second,
+	} {
+		if got := policy.backoff(failures); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", failures, got, want)
+		}
+	}
+
+	defaults := RestartPolicy{}
+	if got := defaults.backoff(1); got != time.Second {
+		t.Errorf("Expected a default initial backoff of 1s, got %v", got)
+	}
+	if got := defaults.backoff(100); got != time.Minute {
+		t.Errorf("Expected a default maximum backoff of 1m, got %v", got)
+	}
+}
+
+// restartRecorder collects restart events with the health observed when each was reported.
+type restartRecorder struct {
+	mu     sync.Mutex
+	events []ServiceRestartEvent
+	health []ServiceHealth
+}
+
+func (r *restartRecorder) attach(svc *SupervisedService) {
+	svc.OnRestart(func(e ServiceRestartEvent) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, e)
+		r.health = append(r.health, svc.Health())
+	})
+}
+
+func (r *restartRecorder) snapshot() ([]ServiceRestartEvent, []ServiceHealth) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ServiceRestartEvent(nil), r.events...), append([]ServiceHealth(nil), r.health...)
+}
+
+func TestSupervisedServiceRestartsOnFailure(t *testing.T) {
+	runs := 0
+	running := make(chan struct{})
+	svc := NewSupervisedService("consumer", RestartPolicy{Mode: RestartOnFailure, InitialBackoff: time.Millisecond},
+		func(ctx context.Context) error {
+			runs++
+			switch runs {
+			case 1:
+				return errors.New("connection reset")
+			case 2:
+				panic("nil message")
+			}
+			close(running)
+			<-ctx.Done()
+			return nil
+		})
+	recorder := &restartRecorder{}
+	recorder.attach(svc)
+
+	host := newTestHost(nil, svc)
+	var lifetimeEvents []ServiceRestartEvent
+	var mu sync.Mutex
+	host.lifetime.(IServiceRestartEvents).OnServiceRestart(func(e ServiceRestartEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		lifetimeEvents = append(lifetimeEvents, e)
+	})
+
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	waitClosed(t, running, "the third run")
+
+	events, health := recorder.snapshot()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 restarts, got %d", len(events))
+	}
+	if events[0].Service != "consumer" || events[0].Attempt != 1 || events[0].Err.Error() != "connection reset" {
+		t.Errorf("Unexpected first restart: %+v", events[0])
+	}
+	if events[1].Attempt != 2 || !strings.Contains(events[1].Err.Error(), "panicked: nil message") {
+		t.Errorf("Expected the panic to be restarted, got %+v", events[1])
+	}
+	if events[0].Delay != time.Millisecond || events[1].Delay != 2*time.Millisecond {
+		t.Errorf("Expected exponential backoff, got %v and %v", events[0].Delay, events[1].Delay)
+	}
+	for i, h := range health {
+		if h.Status != HealthStatusDegraded {
+			t.Errorf("Expected Degraded while waiting for restart %d, got %v", i+1, h.Status)
+		}
+	}
+
+	h := svc.Health()
+	if h.Status != HealthStatusHealthy || h.Restarts != 2 || !strings.Contains(h.LastError.Error(), "nil message") {
+		t.Errorf("Unexpected health after recovery: %+v", h)
+	}
+	mu.Lock()
+	if len(lifetimeEvents) != 2 {
+		t.Errorf("Expected restarts to be reported through the application lifetime, got %d", len(lifetimeEvents))
+	}
+	mu.Unlock()
+
+	if err := host.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if err := svc.ExecuteError(); err != nil {
+		t.Errorf("Expected no error after stopping, got %v", err)
+	}
+}
+
+func TestSupervisedServiceGivesUpAfterMaxAttempts(t *testing.T) {
+	runs := 0
+	svc := NewSupervisedService("consumer", RestartPolicy{Mode: RestartOnFailure, MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		func(ctx context.Context) error {
+			runs++
+			return fmt.Errorf("failure %d", runs)
+		})
+	recorder := &restartRecorder{}
+	recorder.attach(svc)
+
+	svc.StartAsync(context.Background())
+	waitClosed(t, svc.ExecuteTask(), "the service to give up")
+
+	if runs != 3 {
+		t.Errorf("Expected the first run and 2 restarts, got %d runs", runs)
+	}
+	err := svc.ExecuteError()
+	if err == nil || err.Error() != "supervised service consumer gave up after 2 restarts: failure 3" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	h := svc.Health()
+	if h.Status != HealthStatusUnhealthy || h.Restarts != 2 || h.LastError != err {
+		t.Errorf("Unexpected health after giving up: %+v", h)
+	}
+	if events, _ := recorder.snapshot(); len(events) != 2 {
+		t.Errorf("Expected 2 restart events, got %d", len(events))
+	}
+}
+
+func TestSupervisedServiceSuccessResetsFailures(t *testing.T) {
+	runs := 0
+	svc := NewSupervisedService("consumer", RestartPolicy{Mode: RestartAlways, MaxAttempts: 1, InitialBackoff: time.Millisecond},
+		func(ctx context.Context) error {
+			runs++
+			if runs >= 5 {
+				return errors.New("fatal")
+			}
+			if runs%2 == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+	recorder := &restartRecorder{}
+	recorder.attach(svc)
+
+	svc.StartAsync(context.Background())
+	waitClosed(t, svc.ExecuteTask(), "the service to stop")
+
+	// fail, ok, fail, ok, fail, fail: each success resets the count, so the policy gives up at run 6
+	if runs != 6 {
+		t.Errorf("Expected 6 runs, got %d", runs)
+	}
+	events, _ := recorder.snapshot()
+	if len(events) != 5 || events[1].Err != nil || events[1].Delay != time.Millisecond {
+		t.Errorf("Expected RestartAlways to restart after a normal return with the initial backoff, got %+v", events)
+	}
+}
+
+func TestSupervisedServiceRestartNever(t *testing.T) {
+	svc := NewSupervisedService("consumer", RestartPolicy{}, func(ctx context.Context) error {
+		return errors.New("failed")
+	})
+	svc.StartAsync(context.Background())
+	waitClosed(t, svc.ExecuteTask(), "the service to stop")
+
+	if err := svc.ExecuteError(); err == nil || err.Error() != "failed" {
+		t.Errorf("Expected the error to be returned unchanged, got %v", err)
+	}
+	if h := svc.Health(); h.Status != HealthStatusUnhealthy || h.Restarts != 0 {
+		t.Errorf("Unexpected health: %+v", h)
+	}
+}
+
+func TestSupervisedServiceOnFailureCompletes(t *testing.T) {
+	svc := NewSupervisedService("migration", RestartPolicy{Mode: RestartOnFailure}, func(ctx context.Context) error {
+		return nil
+	})
+	svc.StartAsync(context.Background())
+	waitClosed(t, svc.ExecuteTask(), "the service to complete")
+
+	if h := svc.Health(); h.Status != HealthStatusHealthy || h.Restarts != 0 || svc.ExecuteError() != nil {
+		t.Errorf("Expected a normal completion not to be restarted, got %+v", h)
+	}
+}
+
+func TestSupervisedServiceStopDuringBackoff(t *testing.T) {
+	svc := NewSupervisedService("consumer", RestartPolicy{Mode: RestartOnFailure, InitialBackoff: time.Hour},
+		func(ctx context.Context) error { return errors.New("failed") })
+	restarting := make(chan struct{})
+	svc.OnRestart(func(ServiceRestartEvent) { close(restarting) })
+
+	svc.StartAsync(context.Background())
+	waitClosed(t, restarting, "the first restart")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := svc.StopAsync(ctx); err != nil {
+		t.Fatalf("Expected StopAsync to interrupt the backoff, got %v", err)
+	}
+	if svc.ExecuteError() != nil {
+		t.Errorf("Expected stopping during backoff not to be a failure, got %v", svc.ExecuteError())
+	}
+}
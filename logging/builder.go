@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gocrud/csgo/config"
+	"github.com/gocrud/csgo/di"
+)
+
+// LoggingBuilder configures the providers and filters of the logger factory.
+// It is applied when the service provider is built, so it can be changed until then.
+// Corresponds to .NET ILoggingBuilder.
+type LoggingBuilder struct {
+	providers []func(cfg config.IConfiguration) (ILoggerProvider, error)
+	filters   FilterOptions
+}
+
+// AddConsole adds the console provider. The format defaults to Logging:Console:Format.
+func (b *LoggingBuilder) AddConsole(configure ...func(options *ConsoleOptions)) *LoggingBuilder {
+	b.providers = append(b.providers, func(cfg config.IConfiguration) (ILoggerProvider, error) {
+		var options ConsoleOptions
+		if cfg != nil {
+			format, err := ParseFormat(cfg.Get("Logging:Console:Format"))
+			if err != nil {
+				return nil, err
+			}
+			options.Format = format
+		}
+		for _, c := range configure {
+			c(&options)
+		}
+		return NewConsoleProvider(options), nil
+	})
+	return b
+}
+
+// AddFile adds the rolling file provider. The options default to the Logging:File section;
+// the provider is skipped when no path is configured, so it can be enabled from configuration:
+//
+//	Logging:
+//	  File:
+//	    Path: logs/app.log
+//	    MaxSizeMB: 10
+//	    MaxFiles: 5
+func (b *LoggingBuilder) AddFile(configure ...func(options *FileOptions)) *LoggingBuilder {
+	b.providers = append(b.providers, func(cfg config.IConfiguration) (ILoggerProvider, error) {
+		var options FileOptions
+		if cfg != nil {
+			if err := readFileOptions(cfg, &options); err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range configure {
+			c(&options)
+		}
+		if options.Path == "" {
+			return nil, nil
+		}
+		return NewFileProvider(options)
+	})
+	return b
+}
+
+// readFileOptions reads the Logging:File section.
+func readFileOptions(cfg config.IConfiguration, options *FileOptions) error {
+	options.Path = cfg.Get("Logging:File:Path")
+
+	format, err := ParseFormat(cfg.Get("Logging:File:Format"))
+	if err != nil {
+		return err
+	}
+	options.Format = format
+
+	if v := cfg.Get("Logging:File:MaxSizeMB"); v != "" {
+		mb, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid Logging:File:MaxSizeMB %q", v)
+		}
+		options.MaxSize = int64(mb) << 20
+	}
+	if v := cfg.Get("Logging:File:MaxFiles"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid Logging:File:MaxFiles %q", v)
+		}
+		options.MaxFiles = n
+	}
+	return nil
+}
+
+// AddProvider adds a custom provider.
+func (b *LoggingBuilder) AddProvider(provider ILoggerProvider) *LoggingBuilder {
+	b.providers = append(b.providers, func(config.IConfiguration) (ILoggerProvider, error) {
+		return provider, nil
+	})
+	return b
+}
+
+// ClearProviders removes all providers added so far, including the defaults.
+func (b *LoggingBuilder) ClearProviders() *LoggingBuilder {
+	b.providers = nil
+	return b
+}
+
+// SetMinimumLevel sets the level used for categories without a matching filter.
+// Logging:LogLevel:Default in configuration takes precedence.
+func (b *LoggingBuilder) SetMinimumLevel(level LogLevel) *LoggingBuilder {
+	b.filters.MinLevel = level
+	return b
+}
+
+// AddFilter sets the minimum level for categories starting with prefix.
+// A Logging:LogLevel entry with the same prefix takes precedence.
+func (b *LoggingBuilder) AddFilter(prefix string, level LogLevel) *LoggingBuilder {
+	if b.filters.Rules == nil {
+		b.filters.Rules = make(map[string]LogLevel)
+	}
+	b.filters.Rules[prefix] = level
+	return b
+}
+
+// Build creates the logger factory. cfg may be nil.
+func (b *LoggingBuilder) Build(cfg config.IConfiguration) (*LoggerFactory, error) {
+	factory := NewLoggerFactory()
+	for _, create := range b.providers {
+		provider, err := create(cfg)
+		if err != nil {
+			factory.Dispose()
+			return nil, fmt.Errorf("failed to create logger provider: %w", err)
+		}
+		if provider != nil {
+			factory.AddProvider(provider)
+		}
+	}
+
+	factory.SetFilters(b.filters)
+	if cfg != nil {
+		factory.UseConfiguration(cfg)
+	}
+	return factory, nil
+}
+
+// loggingDeps are the dependencies of the logger factory; configuration is optional.
+type loggingDeps struct {
+	di.In
+	Config config.IConfiguration `di:"optional"`
+}
+
+// AddLogging registers ILoggerFactory in the service collection, which makes ILogger[T]
+// injectable for every T, and returns the builder used to configure providers and filters.
+// With no providers the factory discards all records. hosting.CreateDefaultBuilder calls it
+// with the console and file providers:
+//
+//	logging.AddLogging(services).AddConsole().SetMinimumLevel(logging.LevelDebug)
+//
+//	func NewUserService(logger logging.ILogger[UserService]) *UserService
+func AddLogging(services di.IServiceCollection) *LoggingBuilder {
+	builder := &LoggingBuilder{filters: FilterOptions{MinLevel: LevelInformation}}
+
+	services.Add(func(deps loggingDeps) (ILoggerFactory, error) {
+		return builder.Build(deps.Config)
+	})
+
+	return builder
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Format is the record format written by the console and file providers.
+type Format int
+
+const (
+	// FormatText writes key=value records (slog.TextHandler).
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per record (slog.JSONHandler).
+	FormatJSON
+)
+
+// String returns the format name.
+func (f Format) String() string {
+	if f == FormatJSON {
+		return "json"
+	}
+	return "text"
+}
+
+// ParseFormat parses "text" or "json" (case-insensitive).
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text", "":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format %q", s)
+	}
+}
+
+// newHandler creates a slog handler for the format. The handler accepts every level;
+// the factory filters records before they are written.
+func newHandler(format Format, w io.Writer) slog.Handler {
+	options := &slog.HandlerOptions{Level: slogLevelTrace, ReplaceAttr: replaceLevel}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// ConsoleOptions configures the console provider.
+// In configuration, the format is read from Logging:Console:Format.
+type ConsoleOptions struct {
+	Format Format
+	Writer io.Writer // defaults to os.Stdout
+}
+
+// ConsoleProvider writes log records to the console.
+type ConsoleProvider struct {
+	handler slog.Handler
+}
+
+// NewConsoleProvider creates a console provider.
+func NewConsoleProvider(options ConsoleOptions) *ConsoleProvider {
+	w := options.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	return &ConsoleProvider{handler: newHandler(options.Format, w)}
+}
+
+// Handler returns the handler that writes to the console.
+func (p *ConsoleProvider) Handler() slog.Handler {
+	return p.handler
+}
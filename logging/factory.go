@@ -0,0 +1,262 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gocrud/csgo/config"
+)
+
+// ILoggerFactory creates loggers and manages the providers they write to.
+// Corresponds to .NET ILoggerFactory.
+type ILoggerFactory interface {
+	// CreateLogger returns a logger for the category. ILogger[T] calls it for every
+	// record, so implementations should return a cached logger per category without
+	// taking an exclusive lock.
+	CreateLogger(category string) Logger
+
+	// AddProvider adds a provider; existing loggers start writing to it as well.
+	AddProvider(provider ILoggerProvider)
+}
+
+// ILoggerProvider creates the slog handler that writes records to a destination.
+// Level filtering is applied by the factory before records reach the handler.
+// Providers implementing io.Closer are closed when the factory is disposed.
+type ILoggerProvider interface {
+	Handler() slog.Handler
+}
+
+// FilterOptions configures the minimum level per category.
+// A rule applies to categories starting with its key; the longest matching key wins.
+type FilterOptions struct {
+	MinLevel LogLevel
+	Rules    map[string]LogLevel
+}
+
+// levelFor returns the minimum level for the category.
+func (o FilterOptions) levelFor(category string) LogLevel {
+	level, longest := o.MinLevel, -1
+	lower := strings.ToLower(category)
+	for prefix, rule := range o.Rules {
+		if len(prefix) > longest && strings.HasPrefix(lower, strings.ToLower(prefix)) {
+			level, longest = rule, len(prefix)
+		}
+	}
+	return level
+}
+
+// categoryState is shared by all loggers of a category so filter changes apply immediately.
+type categoryState struct {
+	level  slog.LevelVar
+	logger Logger // created once; loggers are immutable and safe for concurrent use
+}
+
+// LoggerFactory is the default ILoggerFactory.
+type LoggerFactory struct {
+	mu           sync.Mutex
+	providers    []ILoggerProvider
+	handlers     atomic.Pointer[[]slog.Handler] // provider handlers, replaced when a provider is added
+	filters      FilterOptions                  // filters configured in code
+	configLevels map[string]LogLevel            // levels from the Logging:LogLevel section
+	configured   FilterOptions                  // filters merged with the configuration levels
+	categories   sync.Map                       // category -> *categoryState; read without the lock, written under mu
+	disposed     bool
+}
+
+// NewLoggerFactory creates a logger factory with the providers and a minimum level of Information.
+func NewLoggerFactory(providers ...ILoggerProvider) *LoggerFactory {
+	f := &LoggerFactory{
+		filters: FilterOptions{MinLevel: LevelInformation},
+	}
+	f.configured = f.filters
+	f.handlers.Store(&[]slog.Handler{})
+	for _, p := range providers {
+		f.AddProvider(p)
+	}
+	return f
+}
+
+// CreateLogger returns the logger for the category, creating it on first use.
+// Existing loggers are returned without locking, so ILogger[T] can call it for every record.
+func (f *LoggerFactory) CreateLogger(category string) Logger {
+	if state, ok := f.categories.Load(category); ok {
+		return state.(*categoryState).logger
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if state, ok := f.categories.Load(category); ok {
+		return state.(*categoryState).logger
+	}
+
+	state := &categoryState{}
+	state.level.Set(f.configured.levelFor(category).Slog())
+	state.logger = &logger{
+		category: category,
+		state:    state,
+		slog:     slog.New(&fanoutHandler{factory: f, state: state}).With(slog.String("category", category)),
+	}
+	f.categories.Store(category, state)
+	return state.logger
+}
+
+// AddProvider adds a provider; existing loggers start writing to it as well.
+func (f *LoggerFactory) AddProvider(provider ILoggerProvider) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.providers = append(f.providers, provider)
+	handlers := append(append([]slog.Handler{}, *f.handlers.Load()...), provider.Handler())
+	f.handlers.Store(&handlers)
+}
+
+// SetFilters replaces the filters configured in code and updates existing loggers.
+// Rules from the Logging:LogLevel configuration section still take precedence.
+func (f *LoggerFactory) SetFilters(filters FilterOptions) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = filters
+	f.refresh()
+}
+
+// UseConfiguration applies the Logging:LogLevel section on top of the filters configured
+// in code and reapplies it whenever the configuration changes:
+//
+//	Logging:
+//	  LogLevel:
+//	    Default: Information
+//	    github.com/gocrud/csgo: Warning
+//
+// Values that are not valid level names are ignored.
+func (f *LoggerFactory) UseConfiguration(cfg config.IConfiguration) {
+	f.loadConfiguration(cfg)
+	cfg.OnChange(func() { f.loadConfiguration(cfg) })
+}
+
+// loadConfiguration reads the Logging:LogLevel section and updates existing loggers.
+func (f *LoggerFactory) loadConfiguration(cfg config.IConfiguration) {
+	levels := make(map[string]LogLevel)
+	for _, section := range cfg.GetSection("Logging:LogLevel").GetChildren() {
+		if level, err := ParseLevel(section.Value()); err == nil {
+			levels[section.Key()] = level
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configLevels = levels
+	f.refresh()
+}
+
+// refresh merges the configuration levels over the code filters and updates the level
+// of every category (caller holds the lock).
+func (f *LoggerFactory) refresh() {
+	configured := FilterOptions{MinLevel: f.filters.MinLevel, Rules: make(map[string]LogLevel)}
+	for prefix, level := range f.filters.Rules {
+		configured.Rules[prefix] = level
+	}
+	for key, level := range f.configLevels {
+		if strings.EqualFold(key, "Default") {
+			configured.MinLevel = level
+		} else {
+			configured.Rules[key] = level
+		}
+	}
+
+	f.configured = configured
+	f.categories.Range(func(category, state any) bool {
+		state.(*categoryState).level.Set(configured.levelFor(category.(string)).Slog())
+		return true
+	})
+}
+
+// Dispose closes the providers that implement io.Closer.
+func (f *LoggerFactory) Dispose() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.disposed {
+		return nil
+	}
+	f.disposed = true
+
+	var errs []error
+	for _, p := range f.providers {
+		if closer, ok := p.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fanoutHandler writes records of one category to every provider handler.
+// Attributes and groups added with WithAttrs/WithGroup are replayed on the provider
+// handlers, so providers added later receive them too.
+type fanoutHandler struct {
+	factory *LoggerFactory
+	state   *categoryState
+	ops     []func(slog.Handler) slog.Handler
+
+	cache atomic.Pointer[fanoutCache]
+}
+
+// fanoutCache holds the provider handlers with ops applied, for one set of providers.
+type fanoutCache struct {
+	source   *[]slog.Handler
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.state.level.Level()
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.resolve() {
+		if handler.Enabled(ctx, r.Level) {
+			if err := handler.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *fanoutHandler) with(op func(slog.Handler) slog.Handler) *fanoutHandler {
+	ops := append(append([]func(slog.Handler) slog.Handler{}, h.ops...), op)
+	return &fanoutHandler{factory: h.factory, state: h.state, ops: ops}
+}
+
+// resolve returns the provider handlers with ops applied, rebuilding them when providers change.
+func (h *fanoutHandler) resolve() []slog.Handler {
+	source := h.factory.handlers.Load()
+	if cache := h.cache.Load(); cache != nil && cache.source == source {
+		return cache.handlers
+	}
+
+	handlers := make([]slog.Handler, len(*source))
+	for i, handler := range *source {
+		for _, op := range h.ops {
+			handler = op(handler)
+		}
+		handlers[i] = handler
+	}
+	h.cache.Store(&fanoutCache{source: source, handlers: handlers})
+	return handlers
+}
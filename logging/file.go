@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileOptions configures the rolling file provider.
+// In configuration, the options are read from the Logging:File section
+// (Path, Format, MaxSizeMB, MaxFiles).
+type FileOptions struct {
+	// Path is the active log file; rolled files are named Path.1 (newest) to Path.N.
+	Path string
+
+	Format Format
+
+	// MaxSize is the size in bytes at which the file is rolled (default 10 MB).
+	MaxSize int64
+
+	// MaxFiles is the number of rolled files kept (default 5).
+	MaxFiles int
+}
+
+// FileProvider writes log records to a file that is rolled by size.
+type FileProvider struct {
+	writer  *rollingWriter
+	handler slog.Handler
+}
+
+// NewFileProvider creates a rolling file provider, creating the directory if needed.
+func NewFileProvider(options FileOptions) (*FileProvider, error) {
+	if options.Path == "" {
+		return nil, errors.New("log file path is required")
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = 10 << 20
+	}
+	if options.MaxFiles <= 0 {
+		options.MaxFiles = 5
+	}
+
+	w := &rollingWriter{path: options.Path, maxSize: options.MaxSize, maxFiles: options.MaxFiles}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return &FileProvider{writer: w, handler: newHandler(options.Format, w)}, nil
+}
+
+// Handler returns the handler that writes to the file.
+func (p *FileProvider) Handler() slog.Handler {
+	return p.handler
+}
+
+// Close closes the file; later records are dropped.
+func (p *FileProvider) Close() error {
+	return p.writer.Close()
+}
+
+// rollingWriter is an io.WriteCloser that rolls the file once it would exceed maxSize.
+type rollingWriter struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// open opens the active file for appending.
+func (w *rollingWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+func (w *rollingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.roll(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// roll shifts Path.i to Path.i+1, dropping the oldest, and reopens an empty active file.
+func (w *rollingWriter) roll() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", w.path, w.maxFiles))
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	// If the rename fails, the active file is reopened and keeps growing
+	os.Rename(w.path, w.path+".1")
+	return w.open()
+}
+
+func (w *rollingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// LogLevel defines logging severity levels.
+// Corresponds to .NET Microsoft.Extensions.Logging.LogLevel.
+type LogLevel int
+
+const (
+	LevelTrace LogLevel = iota
+	LevelDebug
+	LevelInformation
+	LevelWarning
+	LevelError
+	LevelCritical
+	// LevelNone disables logging for a category.
+	LevelNone
+)
+
+// slog levels for the levels slog does not define.
+const (
+	slogLevelTrace    = slog.Level(-8)
+	slogLevelCritical = slog.Level(12)
+)
+
+// String returns the level name.
+func (l LogLevel) String() string {
+	switch l {
+	case LevelTrace:
+		return "Trace"
+	case LevelDebug:
+		return "Debug"
+	case LevelInformation:
+		return "Information"
+	case LevelWarning:
+		return "Warning"
+	case LevelError:
+		return "Error"
+	case LevelCritical:
+		return "Critical"
+	case LevelNone:
+		return "None"
+	default:
+		return "Unknown"
+	}
+}
+
+// Slog returns the equivalent slog level.
+func (l LogLevel) Slog() slog.Level {
+	switch l {
+	case LevelTrace:
+		return slogLevelTrace
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInformation:
+		return slog.LevelInfo
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	case LevelCritical:
+		return slogLevelCritical
+	default:
+		return slog.Level(math.MaxInt32)
+	}
+}
+
+// ParseLevel parses a level name as used in the Logging:LogLevel configuration section.
+// Names are case-insensitive; "Info", "Warn" and "Fatal" are accepted as aliases.
+func ParseLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "information", "info":
+		return LevelInformation, nil
+	case "warning", "warn":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	case "critical", "fatal":
+		return LevelCritical, nil
+	case "none":
+		return LevelNone, nil
+	default:
+		return LevelNone, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+// replaceLevel renders the trace and critical levels by name instead of "DEBUG-4" and "ERROR+4".
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 || a.Key != slog.LevelKey {
+		return a
+	}
+	switch a.Value.Any() {
+	case slogLevelTrace:
+		a.Value = slog.StringValue("TRACE")
+	case slogLevelCritical:
+		a.Value = slog.StringValue("CRITICAL")
+	}
+	return a
+}
@@ -0,0 +1,234 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+
+	"github.com/gocrud/csgo/di"
+)
+
+// Logger writes log messages for a category.
+// Messages use fmt.Sprintf formatting when arguments are given.
+type Logger interface {
+	// LogTrace logs a message at the Trace level.
+	LogTrace(format string, args ...any)
+
+	// LogDebug logs a message at the Debug level.
+	LogDebug(format string, args ...any)
+
+	// LogInformation logs a message at the Information level.
+	LogInformation(format string, args ...any)
+
+	// LogWarning logs a message at the Warning level.
+	LogWarning(format string, args ...any)
+
+	// LogError logs a message and an optional error at the Error level.
+	LogError(err error, format string, args ...any)
+
+	// LogCritical logs a message and an optional error at the Critical level.
+	LogCritical(err error, format string, args ...any)
+
+	// Log writes a structured record: attrs are slog key/value pairs or slog.Attr values.
+	Log(level LogLevel, err error, msg string, attrs ...any)
+
+	// IsEnabled reports whether messages at level are written for this category.
+	IsEnabled(level LogLevel) bool
+
+	// With returns a logger that adds attrs to every record.
+	With(attrs ...any) Logger
+
+	// Category returns the category name.
+	Category() string
+
+	// Slog returns the underlying slog logger, which applies the same level filter.
+	Slog() *slog.Logger
+}
+
+// ILogger is the Logger for the category derived from T. Inject it as a constructor
+// parameter once logging is added to the service collection:
+//
+//	func NewUserService(logger logging.ILogger[UserService]) *UserService
+//
+// The category of ILogger[UserService] declared in package github.com/acme/app
+// is "github.com/acme/app.UserService"; pointer types use the category of their element.
+//
+// ILogger is a di parameter object: the container fills Factory with the registered
+// ILoggerFactory, so no registration is needed for each T. Like other parameter objects it
+// is a constructor parameter only; it cannot be resolved with di.Get or used as a field of
+// another parameter object, where GetLogger or ILoggerFactory can be used instead.
+// The zero value discards all records.
+type ILogger[T any] struct {
+	di.In
+	Factory ILoggerFactory
+}
+
+var _ Logger = ILogger[any]{}
+
+// GetLogger returns the logger whose category is derived from T.
+func GetLogger[T any](factory ILoggerFactory) ILogger[T] {
+	return ILogger[T]{Factory: factory}
+}
+
+// logger returns the factory's logger for the category of T.
+func (l ILogger[T]) logger() Logger {
+	if l.Factory == nil {
+		return discardLogger()
+	}
+	return l.Factory.CreateLogger(categoryFor[T]())
+}
+
+func (l ILogger[T]) LogTrace(format string, args ...any) {
+	l.logger().LogTrace(format, args...)
+}
+
+func (l ILogger[T]) LogDebug(format string, args ...any) {
+	l.logger().LogDebug(format, args...)
+}
+
+func (l ILogger[T]) LogInformation(format string, args ...any) {
+	l.logger().LogInformation(format, args...)
+}
+
+func (l ILogger[T]) LogWarning(format string, args ...any) {
+	l.logger().LogWarning(format, args...)
+}
+
+func (l ILogger[T]) LogError(err error, format string, args ...any) {
+	l.logger().LogError(err, format, args...)
+}
+
+func (l ILogger[T]) LogCritical(err error, format string, args ...any) {
+	l.logger().LogCritical(err, format, args...)
+}
+
+func (l ILogger[T]) Log(level LogLevel, err error, msg string, attrs ...any) {
+	l.logger().Log(level, err, msg, attrs...)
+}
+
+func (l ILogger[T]) IsEnabled(level LogLevel) bool {
+	return l.logger().IsEnabled(level)
+}
+
+func (l ILogger[T]) With(attrs ...any) Logger {
+	return l.logger().With(attrs...)
+}
+
+func (l ILogger[T]) Category() string {
+	return categoryFor[T]()
+}
+
+func (l ILogger[T]) Slog() *slog.Logger {
+	return l.logger().Slog()
+}
+
+// categories caches the category name of each type argument of ILogger.
+var categories sync.Map // reflect.Type -> string
+
+// categoryFor returns the category name derived from T.
+func categoryFor[T any]() string {
+	t := reflect.TypeFor[T]()
+	if category, ok := categories.Load(t); ok {
+		return category.(string)
+	}
+	category := CategoryOf(t)
+	categories.Store(t, category)
+	return category
+}
+
+// CategoryOf returns the category name derived from t: the package path and type name,
+// with pointer types using their element type.
+func CategoryOf(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// discardLogger returns a logger without providers, used by the zero value of ILogger.
+var discardLogger = sync.OnceValue(func() Logger {
+	factory := NewLoggerFactory()
+	factory.SetFilters(FilterOptions{MinLevel: LevelNone})
+	return factory.CreateLogger("")
+})
+
+// logger is the Logger implementation returned by LoggerFactory.
+type logger struct {
+	category string
+	state    *categoryState
+	slog     *slog.Logger
+}
+
+func (l *logger) LogTrace(format string, args ...any) {
+	l.log(LevelTrace, nil, format, args)
+}
+
+func (l *logger) LogDebug(format string, args ...any) {
+	l.log(LevelDebug, nil, format, args)
+}
+
+func (l *logger) LogInformation(format string, args ...any) {
+	l.log(LevelInformation, nil, format, args)
+}
+
+func (l *logger) LogWarning(format string, args ...any) {
+	l.log(LevelWarning, nil, format, args)
+}
+
+func (l *logger) LogError(err error, format string, args ...any) {
+	l.log(LevelError, err, format, args)
+}
+
+func (l *logger) LogCritical(err error, format string, args ...any) {
+	l.log(LevelCritical, err, format, args)
+}
+
+func (l *logger) Log(level LogLevel, err error, msg string, attrs ...any) {
+	if !l.IsEnabled(level) {
+		return
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	l.slog.Log(context.Background(), level.Slog(), msg, attrs...)
+}
+
+func (l *logger) IsEnabled(level LogLevel) bool {
+	return level != LevelNone && level.Slog() >= l.state.level.Level()
+}
+
+func (l *logger) With(attrs ...any) Logger {
+	return &logger{category: l.category, state: l.state, slog: l.slog.With(attrs...)}
+}
+
+func (l *logger) Category() string {
+	return l.category
+}
+
+func (l *logger) Slog() *slog.Logger {
+	return l.slog
+}
+
+// log formats the message and writes it with the optional error attribute.
+func (l *logger) log(level LogLevel, err error, format string, args []any) {
+	if !l.IsEnabled(level) {
+		return
+	}
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	if err != nil {
+		l.slog.LogAttrs(context.Background(), level.Slog(), msg, slog.Any("error", err))
+		return
+	}
+	l.slog.LogAttrs(context.Background(), level.Slog(), msg)
+}
@@ -0,0 +1,223 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocrud/csgo/config"
+	"github.com/gocrud/csgo/di"
+)
+
+type orderService struct {
+	logger ILogger[*orderService]
+}
+
+type orderStore interface {
+	Save(id int) error
+}
+
+type memoryOrderStore struct{}
+
+func (memoryOrderStore) Save(id int) error { return errors.New("disk full") }
+
+type loggingOrderStore struct {
+	inner  orderStore
+	logger ILogger[loggingOrderStore]
+}
+
+func (s *loggingOrderStore) Save(id int) error {
+	err := s.inner.Save(id)
+	if err != nil {
+		s.logger.LogWarning("saving order %d failed: %v", id, err)
+	}
+	return err
+}
+
+// records decodes the JSON lines written by a console provider.
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSON record %q: %v", line, err)
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+func TestILoggerInjection(t *testing.T) {
+	var buf bytes.Buffer
+	services := di.NewServiceCollection()
+	AddLogging(services).AddConsole(func(o *ConsoleOptions) {
+		o.Format = FormatJSON
+		o.Writer = &buf
+	})
+	services.Add(func(logger ILogger[*orderService]) *orderService {
+		return &orderService{logger: logger}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	svc := di.Get[*orderService](provider)
+	svc.logger.LogError(errors.New("timeout"), "order %d failed", 42)
+
+	got := records(t, &buf)
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(got))
+	}
+	if got[0]["category"] != "github.com/gocrud/csgo/logging.orderService" {
+		t.Errorf("Unexpected category: %v", got[0]["category"])
+	}
+	if got[0]["msg"] != "order 42 failed" || got[0]["level"] != "ERROR" || got[0]["error"] != "timeout" {
+		t.Errorf("Unexpected record: %v", got[0])
+	}
+	if c := GetLogger[orderService](di.Get[ILoggerFactory](provider)).Category(); c != svc.logger.Category() {
+		t.Errorf("Expected GetLogger to use the same category, got %q", c)
+	}
+}
+
+func TestILoggerInDecorator(t *testing.T) {
+	var buf bytes.Buffer
+	services := di.NewServiceCollection()
+	AddLogging(services).AddConsole(func(o *ConsoleOptions) {
+		o.Format = FormatJSON
+		o.Writer = &buf
+	})
+	services.Add(func() orderStore { return memoryOrderStore{} })
+	di.Decorate[orderStore](services, func(inner orderStore, logger ILogger[loggingOrderStore]) orderStore {
+		return &loggingOrderStore{inner: inner, logger: logger}
+	})
+	provider := di.BuildServiceProvider(services)
+
+	di.Get[orderStore](provider).Save(7)
+
+	got := records(t, &buf)
+	if len(got) != 1 || got[0]["category"] != "github.com/gocrud/csgo/logging.loggingOrderStore" || got[0]["msg"] != "saving order 7 failed: disk full" {
+		t.Errorf("Unexpected records: %v", got)
+	}
+}
+
+func TestILoggerZeroValueDiscards(t *testing.T) {
+	var logger ILogger[orderService]
+	logger.LogError(errors.New("ignored"), "nothing is written")
+	if logger.IsEnabled(LevelCritical) {
+		t.Error("Expected the zero value to discard all records")
+	}
+	if logger.Category() != "github.com/gocrud/csgo/logging.orderService" {
+		t.Errorf("Unexpected category: %q", logger.Category())
+	}
+}
+
+func TestCreateLoggerDoesNotLockForExistingCategories(t *testing.T) {
+	factory := NewLoggerFactory()
+	created := factory.CreateLogger("app")
+
+	// Hold the factory lock as a concurrent provider or filter change would
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	done := make(chan Logger, 1)
+	go func() { done <- factory.CreateLogger("app") }()
+	select {
+	case got := <-done:
+		if got != created {
+			t.Error("Expected the cached logger to be returned")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the lookup of an existing category not to take the factory lock")
+	}
+}
+
+func TestLevelFiltersFromConfiguration(t *testing.T) {
+	cfg := config.NewConfigurationBuilder().AddInMemoryCollection(map[string]string{
+		"Logging:LogLevel:Default":   "Warning",
+		"Logging:LogLevel:app.noisy": "Error",
+		"Logging:LogLevel:app.db":    "Debug",
+	}).Build()
+
+	var buf bytes.Buffer
+	factory, err := (&LoggingBuilder{}).
+		AddConsole(func(o *ConsoleOptions) { o.Format = FormatJSON; o.Writer = &buf }).
+		AddFilter("app.db", LevelError).
+		Build(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	factory.CreateLogger("app.web").LogInformation("dropped by default level")
+	factory.CreateLogger("app.web").LogWarning("kept")
+	factory.CreateLogger("app.noisy.worker").LogWarning("dropped by prefix rule")
+	factory.CreateLogger("app.db.pool").LogDebug("kept: configuration overrides code filter")
+	factory.CreateLogger("app.db.pool").LogTrace("dropped")
+
+	var messages []string
+	for _, r := range records(t, &buf) {
+		messages = append(messages, r["msg"].(string))
+	}
+	if strings.Join(messages, "|") != "kept|kept: configuration overrides code filter" {
+		t.Errorf("Unexpected messages: %v", messages)
+	}
+
+	if !factory.CreateLogger("app.web").IsEnabled(LevelWarning) || factory.CreateLogger("app.web").IsEnabled(LevelInformation) {
+		t.Error("Expected IsEnabled to follow the configured level")
+	}
+}
+
+func TestRollingFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	provider, err := NewFileProvider(FileOptions{Path: path, MaxSize: 200, MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := NewLoggerFactory(provider).CreateLogger("app")
+	for i := 0; i < 20; i++ {
+		logger.LogInformation("message %d with some padding to fill the file", i)
+	}
+	if err := provider.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Expected %s to exist: %v", name, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("Expected %s to be rolled at 200 bytes, got %d", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected only MaxFiles rolled files to be kept")
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "message 19") {
+		t.Errorf("Expected the active file to hold the latest record, got %q", data)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		"Trace": LevelTrace, "debug": LevelDebug, "Information": LevelInformation, "info": LevelInformation,
+		"WARNING": LevelWarning, "Error": LevelError, "critical": LevelCritical, "None": LevelNone,
+	}
+	for input, want := range tests {
+		if got, err := ParseLevel(input); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
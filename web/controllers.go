@@ -16,7 +16,8 @@ import (
 // 1. 控制器必须是无状态的 - 不要在控制器字段中存储特定于请求的数据
 // 2. 通过处理器中的 HttpContext 参数访问请求数据
 // 3. 通过构造函数注入服务（IServiceProvider 或特定服务）
-// 4. 对于请求范围的服务，在处理器中使用 di.GetRequiredService() 解析
+// 4. 对于请求范围（Scoped）的服务，在处理器中通过 ctx.RequestServices 解析，
+//    不要通过构造函数注入或在控制器字段中缓存
 //
 // 示例：
 //
@@ -51,6 +52,17 @@ func NewControllerBase(services di.IServiceProvider) ControllerBase {
 	return ControllerBase{Services: services}
 }
 
+// RequestServices 返回当前请求的作用域服务提供者。
+// 控制器是单例，Scoped 服务必须通过此方法按请求解析：
+//
+//	uow := di.Get[*UnitOfWork](c.RequestServices(ctx))
+func (c *ControllerBase) RequestServices(ctx *HttpContext) di.IServiceProvider {
+	if ctx.RequestServices != nil {
+		return ctx.RequestServices
+	}
+	return c.Services
+}
+
 // ControllerOptions 表示控制器配置选项。
 type ControllerOptions struct {
 	// EnableEndpointMetadata 启用 OpenAPI 生成的端点元数据
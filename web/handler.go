@@ -16,6 +16,7 @@ type ActionHandlerFunc func(*HttpContext) IActionResult
 
 // MakeToGinHandler 创建一个处理器转换器，将服务注入到 HttpContext 中。
 // 此工厂函数捕获服务并返回转换器函数。
+// 如果请求经过 RequestServicesMiddleware，HttpContext.RequestServices 为请求作用域的提供者。
 // 支持 ActionHandlerFunc 和 gin.HandlerFunc 两种类型：
 //   - ActionHandlerFunc: func(*HttpContext) IActionResult
 //   - gin.HandlerFunc: func(*gin.Context)
@@ -29,9 +30,14 @@ func MakeToGinHandler(services di.IServiceProvider) func(Handler) gin.HandlerFun
 		// 检查是否是 ActionHandlerFunc
 		if actionHandler, ok := handler.(func(*HttpContext) IActionResult); ok {
 			return func(c *gin.Context) {
+				requestServices := RequestServices(c)
+				if requestServices == nil {
+					requestServices = services
+				}
 				ctx := &HttpContext{
-					gin:      c,
-					Services: services,
+					gin:             c,
+					Services:        services,
+					RequestServices: requestServices,
 				}
 				result := actionHandler(ctx)
 				if result != nil {
@@ -19,6 +19,11 @@ type HttpContext struct {
 	// 使用 di.Get[T](ctx.Services) 来解析服务。
 	Services di.IServiceProvider
 
+	// RequestServices 提供对当前请求 DI 作用域的访问。
+	// 使用 di.Get[T](ctx.RequestServices) 来解析 Scoped 服务（当前用户、租户、数据库事务等）。
+	// 作用域在处理器链执行完毕后自动释放。未启用请求作用域时与 Services 相同。
+	RequestServices di.IServiceProvider
+
 	// paramErrors 存储参数验证错误，供新的泛型参数 API 使用。
 	// 使用 web.Path[T], web.Query[T], web.Header[T] 等方法时，
 	// 验证错误会自动收集到这里，并在处理器结束时统一返回。
@@ -0,0 +1,49 @@
+package web
+
+import "github.com/gocrud/csgo/hosting"
+
+// IEndpointModule 由需要注册路由的服务模块实现。
+// 模块通过 builder.AddModule() 添加，其路由在调用 MapModules() 时注册。
+//
+// 示例：
+//
+//	type AuthModule struct{}
+//
+//	func (AuthModule) ConfigureServices(services di.IServiceCollection, cfg config.IConfiguration, env hosting.IHostEnvironment) {
+//	    services.Add(NewTokenService)
+//	}
+//
+//	func (AuthModule) MapEndpoints(app *web.WebApplication) {
+//	    app.POST("/auth/token", issueToken)
+//	}
+type IEndpointModule interface {
+	hosting.IServiceModule
+
+	// MapEndpoints 向应用程序注册模块的路由。
+	MapEndpoints(app *WebApplication)
+}
+
+// AddModule 添加服务模块及其依赖的模块。
+// 每种模块类型只配置一次，依赖的模块先于依赖者配置。
+// 循环依赖，或以不同的设置再次添加同一类型的模块，由 TryBuild 返回错误（Build 会 panic）。
+func (b *WebApplicationBuilder) AddModule(modules ...hosting.IServiceModule) *WebApplicationBuilder {
+	b.hostBuilder.AddModule(modules...)
+	return b
+}
+
+// MapModules 按模块的配置顺序注册所有实现了 IEndpointModule 的模块的路由。
+// 与 MapControllers() 一样，应在 Build() 之后、注册完中间件之后调用。
+//
+// 用法：
+//
+//	app := builder.Build()
+//	app.MapModules()
+//	app.Run()
+func (app *WebApplication) MapModules() *WebApplication {
+	for _, module := range app.modules {
+		if endpoints, ok := module.(IEndpointModule); ok {
+			endpoints.MapEndpoints(app)
+		}
+	}
+	return app
+}
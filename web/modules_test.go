@@ -0,0 +1,120 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocrud/csgo/config"
+	"github.com/gocrud/csgo/di"
+	"github.com/gocrud/csgo/hosting"
+)
+
+type moduleTestStore struct{}
+
+type moduleTestWorker struct{}
+
+func (w *moduleTestWorker) StartAsync(ctx context.Context) error { return nil }
+func (w *moduleTestWorker) StopAsync(ctx context.Context) error  { return nil }
+
+// persistenceTestModule 注册存储服务和托管服务
+type persistenceTestModule struct{ configured *[]string }
+
+func (m persistenceTestModule) ConfigureServices(services di.IServiceCollection, cfg config.IConfiguration, env hosting.IHostEnvironment) {
+	*m.configured = append(*m.configured, "persistence")
+	services.Add(func() *moduleTestStore { return &moduleTestStore{} })
+	services.AddHostedService(func() hosting.IHostedService { return &moduleTestWorker{} })
+}
+
+// authTestModule 依赖 persistenceTestModule 并注册路由
+type authTestModule struct{ configured *[]string }
+
+func (m authTestModule) DependsOn() []hosting.IServiceModule {
+	return []hosting.IServiceModule{persistenceTestModule{configured: m.configured}}
+}
+
+func (m authTestModule) ConfigureServices(services di.IServiceCollection, cfg config.IConfiguration, env hosting.IHostEnvironment) {
+	*m.configured = append(*m.configured, "auth")
+}
+
+func (m authTestModule) MapEndpoints(app *WebApplication) {
+	app.GET("/auth/ping", func(c *HttpContext) IActionResult {
+		di.Get[*moduleTestStore](c.RequestServices)
+		return c.Ok("pong")
+	})
+}
+
+type cycleTestModuleA struct{}
+type cycleTestModuleB struct{}
+
+func (cycleTestModuleA) ConfigureServices(di.IServiceCollection, config.IConfiguration, hosting.IHostEnvironment) {
+}
+func (cycleTestModuleA) DependsOn() []hosting.IServiceModule {
+	return []hosting.IServiceModule{cycleTestModuleB{}}
+}
+func (cycleTestModuleB) ConfigureServices(di.IServiceCollection, config.IConfiguration, hosting.IHostEnvironment) {
+}
+func (cycleTestModuleB) DependsOn() []hosting.IServiceModule {
+	return []hosting.IServiceModule{cycleTestModuleA{}}
+}
+
+func TestAddModule_DependenciesAndEndpoints(t *testing.T) {
+	var configured []string
+	builder := CreateBuilder()
+	builder.AddModule(authTestModule{configured: &configured})
+	builder.AddModule(persistenceTestModule{configured: &configured})
+
+	if strings.Join(configured, ",") != "persistence,auth" {
+		t.Fatalf("依赖模块应先配置且只配置一次, 得到 %v", configured)
+	}
+
+	app := builder.Build()
+	if len(di.GetAll[hosting.IHostedService](app.Services)) != 2 {
+		t.Errorf("期望模块注册的托管服务与 HttpServer 共 2 个")
+	}
+
+	app.MapModules()
+	w := httptest.NewRecorder()
+	app.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/ping", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200, 得到 %d", w.Code)
+	}
+}
+
+func TestAddModule_Cycle(t *testing.T) {
+	builder := CreateBuilder().AddModule(cycleTestModuleA{})
+
+	_, err := builder.TryBuild()
+	if err == nil || !strings.Contains(err.Error(), "module dependency cycle") {
+		t.Fatalf("循环依赖应由 TryBuild 返回错误, 得到 %v", err)
+	}
+
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "module dependency cycle") {
+			t.Errorf("Build 应以循环依赖错误 panic, 得到 %q", msg)
+		}
+	}()
+	builder.Build()
+}
+
+// settingsTestModule 带有设置的模块
+type settingsTestModule struct{ connection string }
+
+func (settingsTestModule) ConfigureServices(di.IServiceCollection, config.IConfiguration, hosting.IHostEnvironment) {
+}
+
+func TestAddModule_DuplicateType(t *testing.T) {
+	builder := CreateBuilder().AddModule(settingsTestModule{connection: "db"}, settingsTestModule{connection: "db"})
+	if _, err := builder.TryBuild(); err != nil {
+		t.Fatalf("设置相同的重复模块应被忽略, 得到 %v", err)
+	}
+
+	builder = CreateBuilder().AddModule(settingsTestModule{connection: "db"}, settingsTestModule{connection: "replica"})
+	_, err := builder.TryBuild()
+	if err == nil || !strings.Contains(err.Error(), "already added with different settings") {
+		t.Errorf("设置不同的重复模块应返回错误, 得到 %v", err)
+	}
+}
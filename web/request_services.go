@@ -0,0 +1,48 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gocrud/csgo/di"
+	"github.com/gocrud/csgo/logging"
+)
+
+// requestServicesKey 是请求作用域服务提供者在 gin.Context 中的存储键。
+const requestServicesKey = "csgo.requestServices"
+
+// RequestServicesMiddleware 为每个 HTTP 请求创建一个 DI 作用域。
+// 作用域的服务提供者通过 HttpContext.RequestServices（或 RequestServices(c)）暴露，
+// 并在整个处理器链执行完毕后释放，即使处理器发生 panic 也会释放。
+// 释放失败时通过 HttpServer 的日志类别记录错误（容器中未注册 ILoggerFactory 时忽略）。
+//
+// WebApplicationBuilder.Build() 会自动注册此中间件，通常无需手动调用。
+func RequestServicesMiddleware(services di.IServiceProvider) gin.HandlerFunc {
+	var logger logging.Logger = logging.ILogger[HttpServer]{}
+	if factory, ok := di.TryGet[logging.ILoggerFactory](services); ok {
+		logger = logging.GetLogger[HttpServer](factory)
+	}
+
+	return func(c *gin.Context) {
+		scope := services.CreateScope()
+		// defer 保证 panic 时（在 Recovery 中间件处理之前）作用域也会被释放
+		defer func() {
+			if err := scope.Dispose(); err != nil {
+				logger.LogError(err, "Failed to dispose request services for %s %s", c.Request.Method, c.Request.URL.Path)
+			}
+		}()
+
+		c.Set(requestServicesKey, scope.ServiceProvider())
+		c.Next()
+	}
+}
+
+// RequestServices 返回当前请求的作用域服务提供者。
+// 用于 gin.HandlerFunc 等无法直接访问 HttpContext 的场景。
+// 如果请求未经过 RequestServicesMiddleware，则返回 nil。
+func RequestServices(c *gin.Context) di.IServiceProvider {
+	if value, exists := c.Get(requestServicesKey); exists {
+		if provider, ok := value.(di.IServiceProvider); ok {
+			return provider
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocrud/csgo/di"
+	"github.com/gocrud/csgo/logging"
+)
+
+type requestState struct {
+	disposed bool
+}
+
+func (s *requestState) Dispose() error {
+	s.disposed = true
+	return nil
+}
+
+func newRequestScopeEngine(t *testing.T, states *[]*requestState, handler Handler) *gin.Engine {
+	t.Helper()
+
+	services := di.NewServiceCollection()
+	services.AddScoped(func() *requestState {
+		s := &requestState{}
+		*states = append(*states, s)
+		return s
+	})
+	provider := di.BuildServiceProvider(services)
+
+	engine := gin.New()
+	engine.Use(gin.Recovery(), RequestServicesMiddleware(provider))
+	engine.GET("/", MakeToGinHandler(provider)(handler))
+	return engine
+}
+
+func TestRequestServices_ScopePerRequest(t *testing.T) {
+	var states []*requestState
+	engine := newRequestScopeEngine(t, &states, func(c *HttpContext) IActionResult {
+		a := di.Get[*requestState](c.RequestServices)
+		b := di.Get[*requestState](c.RequestServices)
+		if a != b {
+			t.Error("同一请求内应返回相同实例")
+		}
+		if a.disposed {
+			t.Error("请求处理期间作用域不应被释放")
+		}
+		return c.NoContent()
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		engine.ServeHTTP(w, req)
+	}
+
+	if len(states) != 2 {
+		t.Fatalf("期望每个请求创建一个实例, 得到 %d", len(states))
+	}
+	if states[0] == states[1] {
+		t.Error("不同请求应使用不同实例")
+	}
+	for _, s := range states {
+		if !s.disposed {
+			t.Error("请求结束后作用域应被释放")
+		}
+	}
+}
+
+func TestRequestServices_DisposedAfterPanic(t *testing.T) {
+	var states []*requestState
+	engine := newRequestScopeEngine(t, &states, func(c *HttpContext) IActionResult {
+		di.Get[*requestState](c.RequestServices)
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	engine.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望 500, 得到 %d", w.Code)
+	}
+	if len(states) != 1 || !states[0].disposed {
+		t.Error("处理器 panic 后作用域也应被释放")
+	}
+}
+
+type failingRequestState struct{}
+
+func (s *failingRequestState) Dispose() error {
+	return errors.New("connection already closed")
+}
+
+func TestRequestServices_LogsDisposeError(t *testing.T) {
+	var buf bytes.Buffer
+	services := di.NewServiceCollection()
+	logging.AddLogging(services).AddConsole(func(o *logging.ConsoleOptions) { o.Writer = &buf })
+	services.AddScoped(func() *failingRequestState { return &failingRequestState{} })
+	provider := di.BuildServiceProvider(services)
+
+	engine := gin.New()
+	engine.Use(RequestServicesMiddleware(provider))
+	engine.GET("/orders", MakeToGinHandler(provider)(func(c *HttpContext) IActionResult {
+		di.Get[*failingRequestState](c.RequestServices)
+		return c.NoContent()
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/orders", nil)
+	engine.ServeHTTP(w, req)
+
+	out := buf.String()
+	if !strings.Contains(out, "Failed to dispose request services for GET /orders") ||
+		!strings.Contains(out, "connection already closed") ||
+		!strings.Contains(out, "category=github.com/gocrud/csgo/web.HttpServer") {
+		t.Errorf("释放失败应通过 HttpServer 日志记录, 得到:\n%s", out)
+	}
+}
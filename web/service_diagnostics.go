@@ -0,0 +1,61 @@
+package web
+
+import (
+	"bytes"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocrud/csgo/di"
+)
+
+// ServiceDiagnosticsOptions 表示服务诊断端点选项。
+type ServiceDiagnosticsOptions struct {
+	// Path 端点路径，默认 /_diag/services
+	Path string
+	// AllowInAllEnvironments 允许在非开发环境中启用端点（默认仅开发环境启用）
+	AllowInAllEnvironments bool
+}
+
+// MapServiceDiagnostics 注册服务依赖图诊断端点（默认仅在开发环境生效）。
+// 通过查询参数 format 选择输出格式：json（默认）、dot、mermaid。
+//
+//	app.MapServiceDiagnostics()
+//	// GET /_diag/services?format=mermaid
+func (app *WebApplication) MapServiceDiagnostics(configure ...func(*ServiceDiagnosticsOptions)) *WebApplication {
+	opts := &ServiceDiagnosticsOptions{Path: "/_diag/services"}
+	if len(configure) > 0 && configure[0] != nil {
+		configure[0](opts)
+	}
+
+	// 依赖图会暴露内部结构，非开发环境默认不注册
+	if !opts.AllowInAllEnvironments && (app.Environment == nil || !app.Environment.IsDevelopment()) {
+		return app
+	}
+
+	app.engine.GET(opts.Path, serviceDiagnosticsHandler(app.Services))
+	return app
+}
+
+// serviceDiagnosticsHandler 按查询参数 format 输出依赖图
+func serviceDiagnosticsHandler(services di.IServiceProvider) gin.HandlerFunc {
+	contentTypes := map[di.GraphFormat]string{
+		di.GraphFormatJSON:    "application/json; charset=utf-8",
+		di.GraphFormatDOT:     "text/vnd.graphviz; charset=utf-8",
+		di.GraphFormatMermaid: "text/plain; charset=utf-8",
+	}
+
+	return func(c *gin.Context) {
+		format := di.GraphFormat(c.DefaultQuery("format", string(di.GraphFormatJSON)))
+		contentType, ok := contentTypes[format]
+		if !ok {
+			c.JSON(400, gin.H{"error": "unsupported format, expected one of: json, dot, mermaid"})
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := di.WriteGraph(&buf, services, format); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(200, contentType, buf.Bytes())
+	}
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocrud/csgo/di"
+)
+
+type diagTestEnvironment struct {
+	name string
+}
+
+func (e diagTestEnvironment) Name() string        { return e.name }
+func (e diagTestEnvironment) IsDevelopment() bool { return e.name == "development" }
+func (e diagTestEnvironment) IsStaging() bool     { return e.name == "staging" }
+func (e diagTestEnvironment) IsProduction() bool  { return e.name == "production" }
+
+type diagTestService struct{}
+
+func newDiagnosticsApp(environment string) *WebApplication {
+	services := di.NewServiceCollection()
+	services.Add(func() *diagTestService { return &diagTestService{} })
+
+	return &WebApplication{
+		engine:      gin.New(),
+		Services:    di.BuildServiceProvider(services),
+		Environment: diagTestEnvironment{name: environment},
+	}
+}
+
+func TestMapServiceDiagnostics_Development(t *testing.T) {
+	app := newDiagnosticsApp("development").MapServiceDiagnostics()
+
+	w := httptest.NewRecorder()
+	app.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_diag/services?format=mermaid", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200, 得到 %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "diagTestService<br/>(Singleton)") {
+		t.Errorf("响应中应包含服务节点, 得到:\n%s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	app.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_diag/services?format=png", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("不支持的格式期望 400, 得到 %d", w.Code)
+	}
+}
+
+func TestMapServiceDiagnostics_Production(t *testing.T) {
+	app := newDiagnosticsApp("production").MapServiceDiagnostics()
+
+	w := httptest.NewRecorder()
+	app.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_diag/services", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("生产环境期望 404, 得到 %d", w.Code)
+	}
+}
@@ -18,6 +18,7 @@ type WebApplication struct {
 	routes      []*router.RouteBuilder
 	groups      []*router.RouteGroupBuilder
 	runtimeUrls *[]string // 指向运行时 URL 的指针（与 HttpServer 共享）
+	modules     []hosting.IServiceModule
 
 	// 带有服务注入的处理器转换器
 	toHandler  func(Handler) gin.HandlerFunc
@@ -2,15 +2,20 @@ package web
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gocrud/csgo/config"
 	"github.com/gocrud/csgo/di"
 	"github.com/gocrud/csgo/hosting"
+	"github.com/gocrud/csgo/logging"
 	"github.com/gocrud/csgo/web/router"
 )
 
@@ -22,6 +27,7 @@ type WebApplicationBuilder struct {
 	Environment   hosting.IHostEnvironment
 	Host          *ConfigureHostBuilder
 	WebHost       *ConfigureWebHostBuilder
+	Logging       *logging.LoggingBuilder
 
 	hostBuilder *hosting.HostBuilder
 }
@@ -37,6 +43,7 @@ func CreateBuilder(args ...string) *WebApplicationBuilder {
 		Services:      hostBuilder.Services,
 		Configuration: hostBuilder.Configuration,
 		Environment:   hostBuilder.Environment,
+		Logging:       hostBuilder.Logging,
 		hostBuilder:   hostBuilder,
 	}
 
@@ -47,7 +54,17 @@ func CreateBuilder(args ...string) *WebApplicationBuilder {
 }
 
 // Build builds the web application.
+// Panics if TryBuild returns an error.
 func (b *WebApplicationBuilder) Build() *WebApplication {
+	app, err := b.TryBuild()
+	if err != nil {
+		panic(fmt.Sprintf("failed to build web application: %v", err))
+	}
+	return app
+}
+
+// TryBuild builds the web application, or returns the errors recorded by AddModule.
+func (b *WebApplicationBuilder) TryBuild() (*WebApplication, error) {
 	// Configure Gin mode
 	gin.SetMode(gin.ReleaseMode)
 	if b.Environment.IsDevelopment() {
@@ -63,18 +80,37 @@ func (b *WebApplicationBuilder) Build() *WebApplication {
 	// Create a shared pointer for runtime URLs
 	runtimeUrls := &[]string{}
 
+	// The service provider is assigned after the host is built; the startup report reads it lazily
+	var services di.IServiceProvider
+	reportTop := b.getStartupReportTop()
+
 	// Register HttpServer as hosted service
-	b.Services.AddHostedService(func() hosting.IHostedService {
-		return NewHttpServer(addr, engine, func() []string {
+	b.Services.AddHostedService(func(loggerFactory logging.ILoggerFactory) hosting.IHostedService {
+		server := NewHttpServer(addr, engine, func() []string {
 			return *runtimeUrls
 		})
+		server.logger = logging.GetLogger[HttpServer](loggerFactory)
+		server.ginLogger = loggerFactory.CreateLogger("github.com/gin-gonic/gin")
+		if reportTop > 0 {
+			server.startupReportTop = reportTop
+			server.startupReport = func() *di.StartupProfile {
+				return di.StartupReport(services)
+			}
+		}
+		return server
 	})
 
 	// Build host using internal HostBuilder (like .NET's approach)
-	host := b.hostBuilder.Build()
+	host, err := b.hostBuilder.TryBuild()
+	if err != nil {
+		return nil, err
+	}
 
 	// Get the service provider
-	services := host.Services()
+	services = host.Services()
+
+	// Create a DI scope per request (must be registered before any route)
+	engine.Use(RequestServicesMiddleware(services))
 
 	// Create web application with shared URL pointer and handler converters
 	app := &WebApplication{
@@ -85,13 +121,14 @@ func (b *WebApplicationBuilder) Build() *WebApplication {
 		routes:      make([]*router.RouteBuilder, 0),
 		groups:      make([]*router.RouteGroupBuilder, 0),
 		runtimeUrls: runtimeUrls, // Shared pointer
+		modules:     b.hostBuilder.Modules(),
 
 		// Initialize handler converters with services injection
 		toHandler:  MakeToGinHandler(services),
 		toHandlers: MakeToGinHandlers(services),
 	}
 
-	return app
+	return app, nil
 }
 
 // ConfigureHostBuilder allows configuring the generic host.
@@ -105,6 +142,13 @@ func (c *ConfigureHostBuilder) ConfigureServices(configure func(di.IServiceColle
 	return c
 }
 
+// ConfigureHostOptions configures the HostOptions of the underlying host.
+// Corresponds to .NET builder.Host.ConfigureHostOptions().
+func (c *ConfigureHostBuilder) ConfigureHostOptions(configure func(*hosting.HostOptions)) *ConfigureHostBuilder {
+	c.builder.hostBuilder.ConfigureHostOptions(configure)
+	return c
+}
+
 // ConfigureWebHostBuilder allows configuring the web host.
 type ConfigureWebHostBuilder struct {
 	builder *WebApplicationBuilder
@@ -125,37 +169,73 @@ func (c *ConfigureWebHostBuilder) UseShutdownTimeout(seconds int) *ConfigureWebH
 	return c
 }
 
+// UseStartupReport configures how many of the slowest service constructors are printed
+// next to the startup banner in Development. 0 disables the report.
+func (c *ConfigureWebHostBuilder) UseStartupReport(top int) *ConfigureWebHostBuilder {
+	c.builder.Configuration.Set("server.startupReport", strconv.Itoa(top))
+	return c
+}
+
+// UseStartupTimeout configures the time allowed for host startup, including service initialization (IInitializable).
+func (c *ConfigureWebHostBuilder) UseStartupTimeout(seconds int) *ConfigureWebHostBuilder {
+	c.builder.Configuration.Set("server.startupTimeout", strconv.Itoa(seconds))
+	return c
+}
+
 // HttpServer is a hosted service that runs the HTTP server.
 type HttpServer struct {
 	*hosting.BackgroundService
 	defaultAddr string
 	getUrls     func() []string // Function to get runtime URLs
 	engine      *gin.Engine
+	logger      logging.Logger
+	ginLogger   logging.Logger // Logs the routes registered on engine
+
+	mu     sync.Mutex
+	server *http.Server // Set once executeAsync starts listening
+
+	// Startup report printed with the banner (Development only)
+	startupReport    func() *di.StartupProfile
+	startupReportTop int
 }
 
 // NewHttpServer creates a new HTTP server.
+// The server discards its log output; the server registered by WebApplicationBuilder.Build
+// logs through the ILoggerFactory resolved from the container.
 func NewHttpServer(addr string, engine *gin.Engine, getUrls func() []string) *HttpServer {
 	server := &HttpServer{
 		BackgroundService: hosting.NewBackgroundService(),
 		defaultAddr:       addr,
 		getUrls:           getUrls,
 		engine:            engine,
+		logger:            logging.ILogger[HttpServer]{},
+		ginLogger:         logging.ILogger[gin.Engine]{},
 	}
 	server.SetExecuteFunc(server.executeAsync)
 	return server
 }
 
 func (s *HttpServer) executeAsync(ctx context.Context) error {
+	// Get actual listen address (runtime URLs override default)
+	addr := s.getListenAddr()
+
+	// Check if port is available and find an alternative if needed
+	originalAddr := addr
+	addr, portChanged := s.ensurePortAvailable(addr)
+
+	server := &http.Server{Addr: addr, Handler: s.engine.Handler()}
+	s.mu.Lock()
+	if ctx.Err() != nil {
+		// Stopped before the server started listening
+		s.mu.Unlock()
+		return nil
+	}
+	s.server = server
+	s.mu.Unlock()
+
 	errChan := make(chan error, 1)
 
 	go func() {
-		// Get actual listen address (runtime URLs override default)
-		addr := s.getListenAddr()
-
-		// Check if port is available and find an alternative if needed
-		originalAddr := addr
-		addr, portChanged := s.ensurePortAvailable(addr)
-
 		displayAddr := addr
 		if strings.HasPrefix(addr, ":") {
 			displayAddr = "http://localhost" + addr
@@ -163,37 +243,88 @@ func (s *HttpServer) executeAsync(ctx context.Context) error {
 			displayAddr = "http://" + addr
 		}
 
-		fmt.Println("========================================")
-		fmt.Println("🚀 Web Application Started")
-		fmt.Println("========================================")
+		s.logger.LogInformation("Web application started")
 		if portChanged {
 			originalPort := extractPort(originalAddr)
 			newPort := extractPort(addr)
-			fmt.Printf("⚠️  Port %s is already in use, using port %s instead\n", originalPort, newPort)
+			s.logger.LogWarning("Port %s is already in use, using port %s instead", originalPort, newPort)
 		}
-		fmt.Printf("📍 Listening on: %s\n", displayAddr)
+		s.logger.LogInformation("Listening on: %s", displayAddr)
 
-		// Check if Swagger UI is registered and print the URL
+		// Check if Swagger UI is registered and log the URL
 		if s.hasSwaggerRoute() {
 			swaggerURL := displayAddr + "/swagger"
-			fmt.Printf("📚 Swagger UI: %s\n", swaggerURL)
+			s.logger.LogInformation("Swagger UI: %s", swaggerURL)
 		}
 
-		fmt.Println("========================================")
-		fmt.Println("")
+		s.printStartupReport()
+		s.logRoutes()
 
-		if err := s.engine.Run(addr); err != nil {
-			errChan <- err
-		}
+		errChan <- server.ListenAndServe()
 	}()
 
 	select {
 	case err := <-errChan:
+		// StopAsync shuts the server down before cancelling ctx
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
 		return err
-	case <-s.StoppingToken():
-		return nil
 	case <-ctx.Done():
-		return ctx.Err()
+		// Only reached if the server was not shut down by StopAsync
+		server.Close()
+		return nil
+	}
+}
+
+// StopAsync gracefully shuts down the HTTP server, then stops the background service.
+// The server stops accepting connections and waits for in-flight requests to complete,
+// so requests never run after the host disposes the service provider. When ctx expires
+// first, the remaining connections are closed and the error is returned.
+func (s *HttpServer) StopAsync(ctx context.Context) error {
+	s.mu.Lock()
+	server := s.server
+	s.mu.Unlock()
+
+	var errs []error
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			server.Close()
+			errs = append(errs, fmt.Errorf("failed to shut down HTTP server gracefully: %w", err))
+		}
+	}
+	if err := s.BackgroundService.StopAsync(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// printStartupReport logs the slowest service constructors, if enabled.
+func (s *HttpServer) printStartupReport() {
+	if s.startupReport == nil {
+		return
+	}
+
+	report := s.startupReport()
+	slowest := report.Slowest(s.startupReportTop)
+	if len(slowest) == 0 {
+		return
+	}
+
+	s.logger.LogInformation("Services built in %v, slowest constructors:", report.BuildDuration.Round(time.Millisecond))
+	for _, c := range slowest {
+		s.logger.Log(logging.LevelInformation, nil, "Slow constructor",
+			"service", c.Service, "duration", c.Total.Round(time.Microsecond))
+	}
+}
+
+// logRoutes logs the routes registered on the engine at Debug level.
+func (s *HttpServer) logRoutes() {
+	if !s.ginLogger.IsEnabled(logging.LevelDebug) {
+		return
+	}
+	for _, route := range s.engine.Routes() {
+		s.ginLogger.LogDebug("%-6s %-25s --> %s", route.Method, route.Path, route.Handler)
 	}
 }
 
@@ -329,6 +460,18 @@ func (b *WebApplicationBuilder) getListenAddress() string {
 	return parseListenAddress(firstUrl)
 }
 
+// getStartupReportTop returns how many slowest constructors to print at startup.
+// Defaults to 5 in Development and 0 (disabled) otherwise.
+func (b *WebApplicationBuilder) getStartupReportTop() int {
+	if !b.Environment.IsDevelopment() {
+		return 0
+	}
+	if top, err := strconv.Atoi(b.Configuration.Get("server.startupReport")); err == nil && top >= 0 {
+		return top
+	}
+	return 5
+}
+
 // parseListenAddress extracts the listen address from a URL.
 // Examples:
 //
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocrud/csgo/logging"
+)
+
+func TestBuildDoesNotChangeGinDebugOutput(t *testing.T) {
+	builder := CreateBuilder()
+	builder.Logging.ClearProviders()
+	builder.Build()
+
+	if gin.DebugPrintFunc != nil {
+		t.Error("Build 不应修改全局的 gin.DebugPrintFunc")
+	}
+}
+
+func TestHttpServerLogsRoutes(t *testing.T) {
+	var buf bytes.Buffer
+	factory := logging.NewLoggerFactory(logging.NewConsoleProvider(logging.ConsoleOptions{Writer: &buf}))
+	factory.SetFilters(logging.FilterOptions{MinLevel: logging.LevelDebug})
+
+	engine := gin.New()
+	engine.GET("/ping", func(c *gin.Context) {})
+
+	server := NewHttpServer(":0", engine, nil)
+	server.logRoutes() // 默认丢弃日志
+	if buf.Len() != 0 {
+		t.Fatalf("未配置日志时不应输出, 得到:\n%s", buf.String())
+	}
+
+	server.ginLogger = factory.CreateLogger("github.com/gin-gonic/gin")
+	server.logRoutes()
+	if !strings.Contains(buf.String(), "GET    /ping") || !strings.Contains(buf.String(), "category=github.com/gin-gonic/gin") {
+		t.Errorf("应通过 gin 类别输出已注册的路由, 得到:\n%s", buf.String())
+	}
+}
+
+// freeAddr 返回一个当前可用的本地监听地址
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestHttpServerStopWaitsForInFlightRequests(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	engine := gin.New()
+	engine.GET("/slow", func(c *gin.Context) {
+		close(entered)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	addr := freeAddr(t)
+	server := NewHttpServer(addr, engine, nil)
+	if err := server.StartAsync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 等待服务器开始监听
+	var resp *http.Response
+	result := make(chan error, 1)
+	go func() {
+		var err error
+		for i := 0; i < 100; i++ {
+			if resp, err = http.Get("http://" + addr + "/slow"); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		result <- err
+	}()
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("请求未到达处理器")
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.StopAsync(context.Background()) }()
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("StopAsync 应等待进行中的请求完成, 提前返回: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		t.Error("停止后不应再接受新连接")
+	}
+
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Errorf("StopAsync 失败: %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("进行中的请求应正常完成, 得到 %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("期望 200, 得到 %d", resp.StatusCode)
+	}
+	if server.ExecuteError() != nil {
+		t.Errorf("正常停止不应返回错误, 得到 %v", server.ExecuteError())
+	}
+}
+
+func TestHttpServerStopTimeout(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	engine := gin.New()
+	engine.GET("/stuck", func(c *gin.Context) {
+		close(entered)
+		<-release
+	})
+
+	addr := freeAddr(t)
+	server := NewHttpServer(addr, engine, nil)
+	server.StartAsync(context.Background())
+	go func() {
+		for i := 0; i < 100; i++ {
+			if resp, err := http.Get("http://" + addr + "/stuck"); err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := server.StopAsync(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("关闭超时应返回 DeadlineExceeded, 得到 %v", err)
+	}
+}